@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Archive by month ---
+
+type archiveMonth struct {
+	month time.Month
+	posts []PostMetadata // Newest first
+}
+
+type archiveYear struct {
+	year   int
+	months []archiveMonth // Newest first, only months with posts
+}
+
+func (y archiveYear) count() int {
+	n := 0
+	for _, mo := range y.months {
+		n += len(mo.posts)
+	}
+	return n
+}
+
+// groupByMonth sorts posts into years and months from their PublishDate,
+// newest first. Posts without a date have no place in the archive.
+func groupByMonth(posts []PostMetadata) []archiveYear {
+	dated := make([]PostMetadata, 0, len(posts))
+	for _, p := range posts {
+		if !p.PublishDate.IsZero() {
+			dated = append(dated, p)
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].PublishDate.After(dated[j].PublishDate)
+	})
+
+	var years []archiveYear
+	for _, p := range dated {
+		year, month := p.PublishDate.Year(), p.PublishDate.Month()
+		if len(years) == 0 || years[len(years)-1].year != year {
+			years = append(years, archiveYear{year: year})
+		}
+		y := &years[len(years)-1]
+		if len(y.months) == 0 || y.months[len(y.months)-1].month != month {
+			y.months = append(y.months, archiveMonth{month: month})
+		}
+		mo := &y.months[len(y.months)-1]
+		mo.posts = append(mo.posts, p)
+	}
+	return years
+}
+
+// archiveRow is one visible line of the archive tree: a year, a month or a post.
+type archiveRow struct {
+	depth  int
+	node   string // Key of the year or month in archiveView.open, "" for a post
+	label  string
+	post   PostMetadata
+	parent int // Row index of the enclosing year or month, -1 for a year
+}
+
+// archiveView is the state of the archive screen.
+type archiveView struct {
+	years  []archiveYear
+	open   map[string]bool // Expanded years ("2024") and months ("2024-03")
+	cursor int
+}
+
+// newArchiveView groups posts for the archive with the newest month open.
+func newArchiveView(posts []PostMetadata) archiveView {
+	a := archiveView{years: groupByMonth(posts), open: make(map[string]bool)}
+	if len(a.years) > 0 {
+		newest := a.years[0]
+		a.open[yearNode(newest)] = true
+		a.open[monthNode(newest, newest.months[0])] = true
+	}
+	return a
+}
+
+func yearNode(y archiveYear) string { return fmt.Sprint(y.year) }
+func monthNode(y archiveYear, mo archiveMonth) string {
+	return fmt.Sprintf("%d-%02d", y.year, mo.month)
+}
+
+// rows flattens the expanded part of the tree into lines.
+func (a archiveView) rows() []archiveRow {
+	var rows []archiveRow
+	for _, y := range a.years {
+		yearRow := len(rows)
+		rows = append(rows, archiveRow{node: yearNode(y), label: fmt.Sprintf("%d (%d)", y.year, y.count()), parent: -1})
+		if !a.open[yearNode(y)] {
+			continue
+		}
+		for _, mo := range y.months {
+			monthRow := len(rows)
+			rows = append(rows, archiveRow{depth: 1, node: monthNode(y, mo), label: fmt.Sprintf("%s (%d)", mo.month, len(mo.posts)), parent: yearRow})
+			if !a.open[monthNode(y, mo)] {
+				continue
+			}
+			for _, p := range mo.posts {
+				rows = append(rows, archiveRow{depth: 2, label: p.Title(), post: p, parent: monthRow})
+			}
+		}
+	}
+	return rows
+}
+
+// move shifts the cursor by delta rows, staying on the tree.
+func (a *archiveView) move(delta int) {
+	a.cursor = max(0, min(a.cursor+delta, len(a.rows())-1))
+}
+
+// toggle opens or closes the year or month under the cursor. It returns the
+// post under the cursor instead, if that's what it's on.
+func (a *archiveView) toggle() (PostMetadata, bool) {
+	rows := a.rows()
+	if a.cursor >= len(rows) {
+		return PostMetadata{}, false
+	}
+	row := rows[a.cursor]
+	if row.node == "" {
+		return row.post, true
+	}
+	a.open[row.node] = !a.open[row.node]
+	return PostMetadata{}, false
+}
+
+// fold closes the node under the cursor, or moves to its parent when it's a
+// post or already closed.
+func (a *archiveView) fold() {
+	rows := a.rows()
+	if a.cursor >= len(rows) {
+		return
+	}
+	row := rows[a.cursor]
+	if row.node != "" && a.open[row.node] {
+		a.open[row.node] = false
+	} else if row.parent >= 0 {
+		a.cursor = row.parent
+	}
+}
+
+// view draws the tree in height lines, scrolled to keep the cursor in sight.
+func (a archiveView) view(width, height int, formatDate dateFormat) string {
+	dimmed := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+	selected := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+
+	rows := a.rows()
+	if len(rows) == 0 {
+		return "No dated posts to archive."
+	}
+	height = max(height, 1)
+	start := max(0, min(a.cursor-height/2, len(rows)-height))
+
+	var lines []string
+	for i := start; i < len(rows) && i < start+height; i++ {
+		row := rows[i]
+		marker := "  "
+		if row.node != "" {
+			marker = "▸ "
+			if a.open[row.node] {
+				marker = "▾ "
+			}
+		}
+		text := strings.Repeat("  ", row.depth) + marker + row.label
+		if i == a.cursor {
+			text = selected.Render(text)
+		}
+		if row.node == "" {
+			text += dimmed.Render(" · " + formatDate(row.post.PublishDate))
+		}
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(width).Render(text))
+	}
+	return strings.Join(lines, "\n")
+}
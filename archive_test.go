@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGroupByMonth(t *testing.T) {
+	post := func(title string, y int, m time.Month, d int) PostMetadata {
+		return PostMetadata{PostTitle: title, PublishDate: time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+	}
+	posts := []PostMetadata{
+		post("Feb 23", 2023, time.February, 1),
+		post("Mar 24 early", 2024, time.March, 2),
+		{PostTitle: "Undated"},
+		post("Dec 23", 2023, time.December, 31),
+		post("Mar 24 late", 2024, time.March, 30),
+		post("Jan 24", 2024, time.January, 1),
+		post("Mar 24 twin", 2024, time.March, 2),
+	}
+	var got []string
+	for _, y := range groupByMonth(posts) {
+		got = append(got, fmt.Sprintf("%d (%d)", y.year, y.count()))
+		for _, mo := range y.months {
+			got = append(got, fmt.Sprintf("  %s: %q", mo.month, titles(mo.posts)))
+		}
+	}
+	want := []string{
+		"2024 (4)",
+		`  March: ["Mar 24 late" "Mar 24 early" "Mar 24 twin"]`,
+		`  January: ["Jan 24"]`,
+		"2023 (2)",
+		`  December: ["Dec 23"]`,
+		`  February: ["Feb 23"]`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if years := groupByMonth([]PostMetadata{{PostTitle: "Undated"}}); len(years) != 0 {
+		t.Errorf("got %+v for only undated posts", years)
+	}
+}
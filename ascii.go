@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/text/unicode/norm"
+)
+
+// --- ASCII-only output ---
+
+// asciiReplacer swaps the symbols the UI draws, and common typography in
+// posts, for ASCII of the same width, so layouts line up as they do otherwise.
+var asciiReplacer = strings.NewReplacer(
+	"─", "-", "━", "-", "═", "=",
+	"│", "|", "┃", "|", "║", "|",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	"╭", "+", "╮", "+", "╯", "+", "╰", "+",
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+",
+	"▸", ">", "▶", ">", "▾", "v", "▼", "v",
+	"←", "<", "→", ">", "↑", "^", "↓", "v",
+	"•", "*", "·", "-", "…", ".", "—", "-", "–", "-",
+	"★", "*", "✦", "*", "●", "*", "⚠", "!", "✓", "v", "✗", "x",
+	"☑", "x", "☐", " ",
+	"“", `"`, "”", `"`, "‘", "'", "’", "'", "«", "<", "»", ">",
+	"█", "#", "▌", "|", "▐", "|", "░", ".", "▒", ":", "▓", "#",
+	"\u00a0", " ",
+)
+
+// toASCII rewrites s, escape sequences and all, to ASCII: known symbols
+// become their look-alikes, accented letters lose the accent, and anything
+// else is replaced by a ? per column it took up.
+func toASCII(s string) string {
+	s = asciiReplacer.Replace(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r <= unicode.MaxASCII {
+			b.WriteRune(r)
+			continue
+		}
+		// é decomposes to e and a combining accent; keep the e
+		if base := []rune(norm.NFD.String(string(r))); base[0] <= unicode.MaxASCII {
+			b.WriteRune(base[0])
+			continue
+		}
+		b.WriteString(strings.Repeat("?", ansi.StringWidth(string(r))))
+	}
+	return b.String()
+}
+
+// uiBorder is border, or the ASCII border when asciiOnly.
+func uiBorder(border lipgloss.Border, asciiOnly bool) lipgloss.Border {
+	if asciiOnly {
+		return lipgloss.ASCIIBorder()
+	}
+	return border
+}
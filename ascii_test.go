@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"╭──╮\n│ok│\n╰──╯", "+--+\n|ok|\n+--+"},
+		{"▸ next → “quoted” — done…", "> next > \"quoted\" - done."},
+		{"☑ done ☐ todo ● unread", "x done   todo * unread"},
+		{"Café naïve Ångström", "Cafe naive Angstrom"},
+		{"日本 🚀!", "???? ??!"},
+		{"\x1b[1mbold\x1b[0m", "\x1b[1mbold\x1b[0m"},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := toASCII(tt.in); got != tt.want {
+			t.Errorf("toASCII(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestASCIIOnlyView(t *testing.T) {
+	post := PostMetadata{
+		PostTitle: "Café ☕ — “Launch” day",
+		Slug:      "cafe",
+		Tags:      []string{"rockets"},
+		Content:   "# Héllo 🚀\n\n- [x] Done\n- [ ] Todo\n\n> Quote…\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n```go\nfmt.Println(\"✓\")\n```\n",
+	}
+	m := testModelWith(t, options{repo: defaultRepoConfig(), asciiOnly: true}, post)
+	m.currentScreen = listScreen
+	views := map[string]string{"list": m.View()}
+	m.showPost(post)
+	views["post"] = m.View()
+	for name, view := range views {
+		for _, r := range view {
+			if r > unicode.MaxASCII {
+				t.Errorf("%s view has %q in:\n%s", name, r, view)
+				break
+			}
+		}
+		if !strings.Contains(view, "Cafe") {
+			t.Errorf("%s view lost the title:\n%s", name, view)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// --- Suggested tags ---
+
+const (
+	maxSuggestedTags = 5
+	// minTermCount is how often a capitalized word must appear mid-sentence
+	// before it's taken for the name of a technology.
+	minTermCount = 3
+)
+
+// languageAliases maps the short names code fences often use to one tag per language.
+var languageAliases = map[string]string{
+	"golang":     "go",
+	"js":         "javascript",
+	"jsx":        "javascript",
+	"mjs":        "javascript",
+	"ts":         "typescript",
+	"tsx":        "typescript",
+	"py":         "python",
+	"rb":         "ruby",
+	"rs":         "rust",
+	"sh":         "shell",
+	"bash":       "shell",
+	"zsh":        "shell",
+	"console":    "shell",
+	"yml":        "yaml",
+	"c++":        "cpp",
+	"cs":         "csharp",
+	"kt":         "kotlin",
+	"md":         "markdown",
+	"mdx":        "markdown",
+	"ps1":        "powershell",
+	"dockerfile": "docker",
+}
+
+// notLanguages are info strings that say nothing about the topic of a post.
+var notLanguages = map[string]bool{
+	"text": true, "txt": true, "plain": true, "plaintext": true,
+	"output": true, "log": true, "diff": true, "math": true,
+}
+
+// commonWords are capitalized often enough mid-sentence to drown out real terms.
+var commonWords = map[string]bool{
+	"i": true, "i'm": true, "i've": true, "i'd": true, "i'll": true,
+	"ok": true, "todo": true, "note": true,
+}
+
+// suggestTags guesses tags from a post's markdown: the languages of its fenced
+// code blocks, in order of first use, then the capitalized words that keep
+// coming up mid-sentence, most frequent first.
+func suggestTags(content string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if tag != "" && !seen[tag] && len(tags) < maxSuggestedTags {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	counts := make(map[string]int)
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		if fence != "" {
+			if isFenceCloser(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f, info, ok := fenceOpener(line); ok {
+			fence = f
+			add(fenceLanguage(info))
+			continue
+		}
+		countTerms(line, counts)
+	}
+
+	terms := make([]string, 0, len(counts))
+	for term, n := range counts {
+		if n >= minTermCount {
+			terms = append(terms, term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	for _, term := range terms {
+		add(term)
+	}
+	return tags
+}
+
+// fenceLanguage is the tag for a code block's info string, e.g. "go" for
+// "golang title=main.go", or "" when it names no language.
+func fenceLanguage(info string) string {
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return ""
+	}
+	// Pandoc-style attributes: {.python .numberLines}
+	lang := strings.ToLower(strings.TrimLeft(fields[0], "{."))
+	lang = strings.TrimRight(lang, "}")
+	if alias, ok := languageAliases[lang]; ok {
+		lang = alias
+	}
+	if notLanguages[lang] {
+		return ""
+	}
+	return lang
+}
+
+// countTerms counts, lowercased, the capitalized words of a prose line that
+// don't start a sentence, where any word is capitalized. Headings are skipped
+// since they're often in title case, and so is markup.
+func countTerms(line string, counts map[string]int) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "import ") || strings.HasPrefix(trimmed, "export ") {
+		return
+	}
+	sentenceStart := true
+	for _, field := range strings.Fields(trimmed) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+		})
+		if word == "" {
+			continue // A list marker, say, which leaves the sentence start where it was
+		}
+		first := sentenceStart
+		sentenceStart = strings.ContainsAny(field[len(field)-1:], ".!?:")
+		if first || strings.ContainsAny(field, "<>/=") {
+			continue
+		}
+		word = strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s")
+		if r := []rune(word); len(r) > 1 && unicode.IsUpper(r[0]) {
+			if term := strings.ToLower(word); !commonWords[term] {
+				counts[term]++
+			}
+		}
+	}
+}
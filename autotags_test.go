@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFenceLanguage(t *testing.T) {
+	tests := []struct {
+		info, want string
+	}{
+		{"go", "go"},
+		{"golang", "go"},
+		{"Go", "go"},
+		{"golang title=main.go", "go"},
+		{"tsx", "typescript"},
+		{"{.python .numberLines}", "python"},
+		{"{.rb}", "ruby"},
+		{"bash", "shell"},
+		{"Dockerfile", "docker"},
+		{"haskell", "haskell"},
+		{"text", ""},
+		{"diff", ""},
+		{"", ""},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		if got := fenceLanguage(tt.info); got != tt.want {
+			t.Errorf("fenceLanguage(%q) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestTagsFromCode(t *testing.T) {
+	content := "Intro\n\n```golang\nfunc main() {}\n```\n\n```text\noutput\n```\n\n~~~ {.py}\n```js\n~~~\n\n```go\nmore\n```\n\n```sh\nls\n```\n"
+	want := []string{"go", "python", "shell"}
+	if got := suggestTags(content); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSuggestedTagsShown(t *testing.T) {
+	post, err := parsePost("post.mdx", []byte("---\ntitle: Untagged\nslug: untagged\n---\n```go\nfunc main() {}\n```\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, autoTags := range []bool{false, true} {
+		m := testModelWith(t, options{repo: defaultRepoConfig(), autoTags: autoTags}, post)
+		if got := strings.Contains(m.metadataBlock(post, 80), "Suggested tags: go"); got != autoTags {
+			t.Errorf("with autoTags %v, suggested tags shown: %v", autoTags, got)
+		}
+	}
+}
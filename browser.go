@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Opening links ---
+
+type openedURLMsg struct {
+	url string
+	err error
+}
+
+// openURLCmd opens url in the local default browser.
+func openURLCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+		if err := cmd.Start(); err != nil {
+			return openedURLMsg{url: url, err: fmt.Errorf("opening %s: %w", url, err)}
+		}
+		go cmd.Wait() // Reap the launcher, nothing to report once it has started
+		return openedURLMsg{url: url}
+	}
+}
+
+const (
+	githubFileURLFormat = "https://github.com/%s/%s/blob/HEAD/%s" // A file on the repo's default branch
+	gistFileURLFormat   = "https://gist.github.com/%s#file-%s"
+)
+
+var gistAnchorRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sourceWebURL is the GitHub page of the file p was loaded from, or "" when
+// p didn't come from GitHub.
+func (m model) sourceWebURL(p PostMetadata) string {
+	switch {
+	case p.SourcePath == "":
+		return ""
+	case m.opts.gistID != "":
+		// Gist pages anchor each file as "file-" plus its name in lowercase kebab case
+		anchor := gistAnchorRe.ReplaceAllString(strings.ToLower(p.SourcePath), "-")
+		return fmt.Sprintf(gistFileURLFormat, m.opts.gistID, anchor)
+	default:
+		return fmt.Sprintf(githubFileURLFormat, m.opts.repo.owner, m.opts.repo.name, p.SourcePath)
+	}
+}
+
+// openSourceCmd opens the selected post's source file on GitHub, or copies its
+// URL over SSH, where a browser on the server is no use to the reader.
+func (m model) openSourceCmd() tea.Cmd {
+	if m.selectedPost == nil {
+		return nil
+	}
+	url := m.sourceWebURL(*m.selectedPost)
+	if url == "" {
+		return m.setStatus("This post has no source file on GitHub")
+	}
+	if m.opts.sshMode {
+		return copyCmd(m.clipboard, url, "the source URL")
+	}
+	return openURLCmd(url)
+}
+
+// webURL is p's page on the blog, for a repo named after the site's domain
+// as space-coast.dev is, or "" when there's no telling where p is published.
+func (m model) webURL(p PostMetadata) string {
+	if m.opts.gistID != "" || p.Slug == "" || !strings.Contains(m.opts.repo.name, ".") {
+		return ""
+	}
+	return "https://" + m.opts.repo.name + "/" + p.Slug
+}
+
+// openWebCmd opens p's page on the blog, images and all. Over SSH it only
+// shows the URL, as the browser would open on the server.
+func (m model) openWebCmd(p PostMetadata) tea.Cmd {
+	url := m.webURL(p)
+	switch {
+	case url == "":
+		return m.setStatus("This post has no web page")
+	case m.opts.sshMode:
+		return m.setStatus("Read it on the web at " + url)
+	}
+	return openURLCmd(url)
+}
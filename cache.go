@@ -0,0 +1,146 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Offline cache of fetched posts ---
+
+// cacheVersion changes when postCache's encoding does, so a cache written
+// in an older one is fetched afresh rather than misread. 2 has PostMetadata's
+// frontmatter names, such as title, and 3 its PlainText.
+const cacheVersion = 3
+
+// postCache is what's kept on disk from the last successful fetch.
+type postCache struct {
+	Version   int            `json:"version"`
+	FetchedAt time.Time      `json:"fetchedAt"`
+	Commit    string         `json:"commit,omitempty"` // Repo commit the posts match, with --incremental
+	Posts     []PostMetadata `json:"posts"`
+}
+
+// cacheNameRe matches what can't go in a cache file name.
+var cacheNameRe = regexp.MustCompile(`[^A-Za-z0-9._]+`)
+
+// cachePath returns where posts from a source are cached, under the user's
+// cache directory: posts.json for the Space Coast Devs blog,
+// gist-<id>-<hash>.json for a Gist and repo-<owner>-<repo>-<path>-<hash>.json
+// for another repo.
+func cachePath(gistID string, repo repoConfig) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := "posts"
+	switch {
+	case gistID != "":
+		name = cacheName("gist", gistID)
+	case repo.owner != defaultRepoOwner || repo.name != defaultRepoName || repo.path != defaultRepoPath:
+		name = cacheName("repo", repo.owner, repo.name, repo.path)
+	}
+	return filepath.Join(dir, "bbs", name+".json"), nil
+}
+
+// cacheName names a source's cache file after kind and parts, readably with
+// what can't go in a file name replaced, then with a hash of the parts
+// themselves, as replacing can make different ones alike: a-b/c and a/b-c.
+func cacheName(kind string, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	readable := strings.Trim(cacheNameRe.ReplaceAllString(strings.Join(parts, "-"), "-"), "-")
+	return kind + "-" + readable + "-" + hex.EncodeToString(sum[:4])
+}
+
+// withCache wraps a fetch so a successful one is saved to path, and a failed
+// one falls back to what was saved last, unless refresh is set. Posts from the
+// cache come with the time they were fetched in cachedAt.
+func withCache(fetch tea.Cmd, path string, refresh bool) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := fetch().(postsLoadedMsg)
+		if !ok {
+			return msg
+		}
+		if msg.err == nil {
+			if err := saveCache(path, postCache{FetchedAt: time.Now(), Commit: msg.commit, Posts: msg.posts}); err != nil {
+				log.Printf("Error caching posts: %v", err)
+			}
+			return msg
+		}
+		if refresh {
+			return msg
+		}
+		cache, err := loadCache(path)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("Error reading cached posts: %v", err)
+			}
+			return msg
+		}
+		log.Printf("Fetching posts failed, using the cache from %s: %v", cache.FetchedAt.Format(time.RFC3339), msg.err)
+		return postsLoadedMsg{posts: cache.Posts, cachedAt: cache.FetchedAt}
+	}
+}
+
+func loadCache(path string) (postCache, error) {
+	var cache postCache
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if cache.Version != cacheVersion {
+		return postCache{}, fmt.Errorf("%s is from an older version: %w", path, fs.ErrNotExist)
+	}
+	return cache, nil
+}
+
+// saveCache writes the cache with writeFileAtomic. SSH sessions may save at
+// the same time; each writes its own temporary file.
+func saveCache(path string, cache postCache) error {
+	cache.Version = cacheVersion
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic writes data through a temporary file renamed into place,
+// so a crash mid-write can't leave a truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// offlineNotice tells the reader the posts shown are from the cache.
+func offlineNotice(cachedAt time.Time, formatDate dateFormat) string {
+	return fmt.Sprintf("Offline: showing cached posts from %s %s", formatDate(cachedAt), cachedAt.Format("15:04"))
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestLoadCacheVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "posts.json")
+	if err := saveCache(path, postCache{Posts: []PostMetadata{{PostTitle: "Kept"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if cache, err := loadCache(path); err != nil || len(cache.Posts) != 1 || cache.Posts[0].PostTitle != "Kept" {
+		t.Fatalf("got %+v, %v", cache, err)
+	}
+
+	// Before versions, titles were saved as PostTitle
+	if err := os.WriteFile(path, []byte(`{"fetchedAt": "2024-01-01T00:00:00Z", "posts": [{"PostTitle": "Old"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cache, err := loadCache(path); !errors.Is(err, fs.ErrNotExist) || len(cache.Posts) != 0 {
+		t.Errorf("old cache gave %+v, %v; want it treated as missing", cache, err)
+	}
+}
+
+func TestOfflineMetadataOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "posts.json")
+	cached := PostMetadata{PostTitle: "Cached", Slug: "cached", SourceURL: "http://127.0.0.1:0/cached.mdx", Content: "Saved for the train"}
+	if err := saveCache(path, postCache{FetchedAt: time.Now(), Posts: []PostMetadata{cached}}); err != nil {
+		t.Fatal(err)
+	}
+	offline := func() tea.Msg { return postsLoadedMsg{err: errors.New("no network")} }
+	msg := metadataOnly(withCache(offline, path, false))().(postsLoadedMsg)
+	if msg.cachedAt.IsZero() || len(msg.posts) != 1 {
+		t.Fatalf("got %+v, want the cached post", msg)
+	}
+
+	m := initialModel(options{repo: defaultRepoConfig(), metadataOnly: true}, settings{FirstRunDone: true})
+	m = step(m, tea.WindowSizeMsg{Width: 80, Height: 40})
+	m = step(m, msg)
+	m.currentScreen = listScreen
+	opened, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	post := opened.(model)
+	if post.selectedPost == nil || post.loadingBody || post.selectedPost.Content != cached.Content {
+		t.Fatalf("opened %+v, loading body %t; want the cached body", post.selectedPost, post.loadingBody)
+	}
+	if cmd != nil {
+		if _, fetching := cmd().(postBodyMsg); fetching {
+			t.Error("opening the post fetched its body")
+		}
+	}
+	if view := ansi.Strip(post.View()); !strings.Contains(view, "Saved for the train") {
+		t.Errorf("post view doesn't show the body:\n%s", view)
+	}
+}
+
+func TestCachePath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/cache")
+	repo := func(owner, name, path string) repoConfig { return repoConfig{owner: owner, name: name, path: path} }
+	tests := []struct {
+		name   string
+		gistID string
+		repo   repoConfig
+		want   string
+	}{
+		{"the blog", "", defaultRepoConfig(), "/cache/bbs/posts.json"},
+		{"a Gist", "abc123", defaultRepoConfig(), "/cache/bbs/gist-abc123-"},
+		{"another repo", "", repo("me", "blog", "content/posts"), "/cache/bbs/repo-me-blog-content-posts-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cachePath(tt.gistID, tt.repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(got, tt.want) || !strings.HasSuffix(got, ".json") {
+				t.Errorf("got %s, want %s…", got, tt.want)
+			}
+		})
+	}
+
+	// Names that read the same once sanitized still get files of their own
+	paths := make(map[string]repoConfig)
+	for _, r := range []repoConfig{
+		repo("a-b", "c", "posts"), repo("a", "b-c", "posts"), repo("a", "b", "c-posts"), repo("a", "b", "c/posts"),
+	} {
+		path, err := cachePath("", r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other, ok := paths[path]; ok {
+			t.Errorf("%+v and %+v share %s", r, other, path)
+		}
+		paths[path] = r
+	}
+	if again, _ := cachePath("", repo("a-b", "c", "posts")); paths[again] != repo("a-b", "c", "posts") {
+		t.Errorf("the same repo moved to %s", again)
+	}
+}
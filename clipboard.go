@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- Clipboard ---
+
+type copiedMsg struct {
+	what string // What was copied, for the status line, e.g. "3 footnote URLs"
+	err  error
+}
+
+// maxOSC52Size is the most text copied over OSC 52. Terminals drop longer
+// sequences without a word, tmux and hterm from around 100 KB of base64, so
+// longer text is refused with errTooLargeToCopy instead.
+const maxOSC52Size = 64 << 10
+
+var errTooLargeToCopy = errors.New("too large to copy over OSC 52")
+
+// copyCmd puts text on the reader's clipboard. Over SSH the server's clipboard
+// is no use, so w is the session and the text travels to the reader's terminal
+// as an OSC 52 sequence; locally (w is nil) the system clipboard is used.
+func copyCmd(w io.Writer, text, what string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if w != nil {
+			if len(text) > maxOSC52Size {
+				return copiedMsg{what: what, err: fmt.Errorf("copying %s (%d KB): %w", what, len(text)>>10, errTooLargeToCopy)}
+			}
+			_, err = io.WriteString(w, ansi.SetSystemClipboard(text))
+		} else {
+			err = clipboard.WriteAll(text)
+		}
+		if err != nil {
+			return copiedMsg{what: what, err: fmt.Errorf("copying %s: %w", what, err)}
+		}
+		return copiedMsg{what: what}
+	}
+}
+
+// copyFootnotesCmd copies the selected post's footnote URLs, one per line.
+func (m model) copyFootnotesCmd() tea.Cmd {
+	if m.opts.links == linksInline {
+		return m.setStatus("Links are left inline (--links inline)")
+	}
+	if len(m.footnoteURLs) == 0 {
+		return m.setStatus("No links in this post")
+	}
+	what := fmt.Sprintf("%d footnote URLs", len(m.footnoteURLs))
+	if len(m.footnoteURLs) == 1 {
+		what = "1 footnote URL"
+	}
+	return copyCmd(m.clipboard, strings.Join(m.footnoteURLs, "\n"), what)
+}
+
+// copyHTMLCmd copies the selected post converted to HTML. A body still being
+// fetched isn't copied, as that would leave only the title.
+func (m *model) copyHTMLCmd() tea.Cmd {
+	if m.selectedPost == nil {
+		return nil
+	}
+	if m.loadingBody {
+		return m.setStatus("Post is still loading")
+	}
+	html, err := postHTML(*m.selectedPost)
+	if err != nil {
+		return func() tea.Msg { return copiedMsg{what: "the post as HTML", err: err} }
+	}
+	return copyCmd(m.clipboard, html, "the post as HTML")
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Collapsing long code blocks ---
+
+// collapsedMarkerHint ends every collapsed-block marker; it's how markers are
+// found again in the rendered output.
+const collapsedMarkerHint = "press e to expand"
+
+// fenceOpener returns the fence (e.g. "```" or "~~~~") and info string when
+// line opens a fenced code block.
+func fenceOpener(line string) (fence, info string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", "", false
+	}
+	for _, ch := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, ch+ch+ch) {
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, ch))
+			return trimmed[:n], strings.TrimSpace(trimmed[n:]), true
+		}
+	}
+	return "", "", false
+}
+
+// isFenceCloser reports whether line closes a block opened with fence.
+func isFenceCloser(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// collapseCodeBlocks replaces fenced code blocks longer than threshold lines
+// with a one-line marker, unless their index (counting every fenced block in
+// the document from 0) is in expanded. It returns the indexes it collapsed, in
+// document order. A threshold of 0 or less disables collapsing.
+func collapseCodeBlocks(markdown string, threshold int, expanded map[int]bool) (string, []int) {
+	if threshold <= 0 {
+		return markdown, nil
+	}
+
+	lines := strings.Split(markdown, "\n")
+	var out []string
+	var collapsed []int
+	block := 0
+	for i := 0; i < len(lines); i++ {
+		fence, info, ok := fenceOpener(lines[i])
+		if !ok {
+			out = append(out, lines[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !isFenceCloser(lines[end], fence) {
+			end++
+		}
+		// An unclosed fence runs to the end of the document, as in CommonMark
+		codeLines := end - i - 1
+		if codeLines > threshold && !expanded[block] {
+			lang := "code"
+			if fields := strings.Fields(info); len(fields) > 0 {
+				lang = strings.ToUpper(fields[0][:1]) + fields[0][1:]
+			}
+			out = append(out, "", fmt.Sprintf("*[+] %d lines of %s — %s*", codeLines, lang, collapsedMarkerHint), "")
+			collapsed = append(collapsed, block)
+		} else {
+			out = append(out, lines[i:min(end+1, len(lines))]...)
+		}
+		block++
+		i = end
+	}
+	return strings.Join(out, "\n"), collapsed
+}
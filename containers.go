@@ -0,0 +1,150 @@
+package main
+
+import (
+	"strings"
+)
+
+// --- Spoiler and warning containers ---
+
+// spoilerMarkerHint ends every hidden-spoiler marker, like collapsedMarkerHint.
+const spoilerMarkerHint = "press v to reveal"
+
+// containerOpener returns the kind (lowercased) and title of a ":::kind title"
+// line. A bare ":::" closes a container instead.
+func containerOpener(line string) (kind, title string, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, ":::") {
+		return "", "", false
+	}
+	rest := strings.TrimSpace(strings.TrimLeft(trimmed, ":"))
+	if rest == "" {
+		return "", "", false
+	}
+	kind, title, _ = strings.Cut(rest, " ")
+	return strings.ToLower(kind), strings.TrimSpace(title), true
+}
+
+func isContainerCloser(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, ":::") && strings.Trim(trimmed, ":") == ""
+}
+
+// containerEnd returns the index of the line closing the container opened at
+// lines[start], skipping nested containers and fenced code, or -1 if it's
+// never closed.
+func containerEnd(lines []string, start int) int {
+	depth := 0
+	for i := start + 1; i < len(lines); i++ {
+		if fence, _, ok := fenceOpener(lines[i]); ok {
+			for i++; i < len(lines) && !isFenceCloser(lines[i], fence); i++ {
+			}
+			continue
+		}
+		if _, _, ok := containerOpener(lines[i]); ok {
+			depth++
+		} else if isContainerCloser(lines[i]) {
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// renderContainers rewrites ":::warning" containers as quoted warning boxes and
+// hides ":::spoiler" containers behind a one-line marker unless their index
+// (counting every spoiler in the document from 0) is in revealed. It returns
+// the indexes still hidden, in document order. Other container kinds are left
+// as they are.
+func renderContainers(markdown string, revealed map[int]bool) (string, []int) {
+	var hidden []int
+	spoiler := 0
+
+	var render func(lines []string) []string
+	render = func(lines []string) []string {
+		var out []string
+		for i := 0; i < len(lines); i++ {
+			if fence, _, ok := fenceOpener(lines[i]); ok {
+				end := i + 1
+				for end < len(lines) && !isFenceCloser(lines[end], fence) {
+					end++
+				}
+				out = append(out, lines[i:min(end+1, len(lines))]...)
+				i = end
+				continue
+			}
+			kind, title, ok := containerOpener(lines[i])
+			if !ok || (kind != "spoiler" && kind != "warning") {
+				out = append(out, lines[i])
+				continue
+			}
+
+			end := containerEnd(lines, i)
+			if end < 0 {
+				// Left as written, rather than hiding the rest of the post
+				out = append(out, lines[i])
+				continue
+			}
+			body := lines[i+1 : end]
+			i = end
+			if kind == "warning" {
+				if title == "" {
+					title = "Warning"
+				}
+				out = append(out, "", "> ⚠ **"+title+"**", ">")
+				out = append(out, quoteLines(render(body))...)
+				out = append(out, "")
+				continue
+			}
+
+			index := spoiler
+			spoiler++
+			label := "spoiler"
+			if title != "" {
+				label += ": " + title
+			}
+			if !revealed[index] {
+				hidden = append(hidden, index)
+				spoiler += countSpoilers(body) // Keep later indexes stable whether or not this one is open
+				out = append(out, "", "*["+label+"] — "+spoilerMarkerHint+"*", "")
+				continue
+			}
+			out = append(out, "", "> **"+strings.ToUpper(label[:1])+label[1:]+"**", ">")
+			out = append(out, quoteLines(render(body))...)
+			out = append(out, "")
+		}
+		return out
+	}
+
+	return strings.Join(render(strings.Split(markdown, "\n")), "\n"), hidden
+}
+
+// countSpoilers counts the spoiler containers among lines, outside fenced code.
+func countSpoilers(lines []string) int {
+	n := 0
+	fence := ""
+	for _, line := range lines {
+		if fence != "" {
+			if isFenceCloser(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f, _, ok := fenceOpener(line); ok {
+			fence = f
+		} else if kind, _, ok := containerOpener(line); ok && kind == "spoiler" {
+			n++
+		}
+	}
+	return n
+}
+
+// quoteLines puts lines inside a markdown blockquote.
+func quoteLines(lines []string) []string {
+	quoted := make([]string, len(lines))
+	for i, line := range lines {
+		quoted[i] = strings.TrimRight("> "+line, " ")
+	}
+	return quoted
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenderContainers(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []string
+		revealed map[int]bool
+		want     []string
+		hidden   []int
+	}{
+		{
+			name:   "spoiler hidden",
+			in:     []string{"Before", ":::spoiler Ending", "It was a dream", ":::", "After"},
+			want:   []string{"Before", "", "*[spoiler: Ending] — press v to reveal*", "", "After"},
+			hidden: []int{0},
+		},
+		{
+			name:     "spoiler revealed",
+			in:       []string{":::spoiler", "It was a dream", ":::"},
+			revealed: map[int]bool{0: true},
+			want:     []string{"", "> **Spoiler**", ">", "> It was a dream", ""},
+		},
+		{
+			name: "warning",
+			in:   []string{":::WARNING Hot", "Don't touch", ":::"},
+			want: []string{"", "> ⚠ **Hot**", ">", "> Don't touch", ""},
+		},
+		{
+			name:   "spoiler nested in a warning",
+			in:     []string{":::warning", "Careful", ":::spoiler", "Secret", ":::", ":::", "After"},
+			want:   []string{"", "> ⚠ **Warning**", ">", "> Careful", ">", "> *[spoiler] — press v to reveal*", ">", "", "After"},
+			hidden: []int{0},
+		},
+		{
+			name:     "spoilers nested in a revealed spoiler",
+			in:       []string{":::spoiler", ":::spoiler Inner", "Deeper", ":::", ":::", ":::spoiler Last", "x", ":::"},
+			revealed: map[int]bool{0: true},
+			want: []string{
+				"", "> **Spoiler**", ">", ">", "> *[spoiler: Inner] — press v to reveal*", ">", "",
+				"", "*[spoiler: Last] — press v to reveal*", "",
+			},
+			hidden: []int{1, 2},
+		},
+		{
+			name:   "hidden spoiler keeps later indexes",
+			in:     []string{":::spoiler", ":::spoiler", "x", ":::", ":::", ":::spoiler", "y", ":::"},
+			want:   []string{"", "*[spoiler] — press v to reveal*", "", "", "*[spoiler] — press v to reveal*", ""},
+			hidden: []int{0, 2},
+		},
+		{
+			name: "unclosed spoiler",
+			in:   []string{"Intro", ":::spoiler Oops", "The rest", "of the post"},
+			want: []string{"Intro", ":::spoiler Oops", "The rest", "of the post"},
+		},
+		{
+			name:   "unclosed inside a closed one",
+			in:     []string{":::spoiler", ":::warning", "Text", ":::", "More"},
+			want:   []string{":::spoiler", "", "> ⚠ **Warning**", ">", "> Text", "", "More"},
+			hidden: nil,
+		},
+		{
+			name: "closer in a code fence",
+			in:   []string{":::spoiler", "```", ":::", "```"},
+			want: []string{":::spoiler", "```", ":::", "```"},
+		},
+		{
+			name: "container in a code fence",
+			in:   []string{"```md", ":::spoiler", "x", ":::", "```"},
+			want: []string{"```md", ":::spoiler", "x", ":::", "```"},
+		},
+		{
+			name: "other kinds left alone",
+			in:   []string{":::note", "x", ":::"},
+			want: []string{":::note", "x", ":::"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hidden := renderContainers(strings.Join(tt.in, "\n"), tt.revealed)
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+			if !slices.Equal(hidden, tt.hidden) {
+				t.Errorf("got hidden %v, want %v", hidden, tt.hidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Jump to date ---
+
+// parseJumpDate reads a YYYY, YYYY-MM or YYYY-MM-DD date and returns the end
+// of the period it names (exclusive), so "2023" means anything before 2024.
+func parseJumpDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []struct {
+		format              string
+		years, months, days int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	} {
+		if t, err := time.Parse(layout.format, s); err == nil {
+			return t.AddDate(layout.years, layout.months, layout.days), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (try 2023, 2023-06 or 2023-06-15)", s)
+}
+
+// jumpTarget returns the index of the newest item published before end, which
+// in the default newest-first sort is the first such item, or -1 if none is.
+// Undated posts are never a target.
+func jumpTarget(items []list.Item, end time.Time) int {
+	target := -1
+	for i, item := range items {
+		p, ok := item.(PostMetadata)
+		if !ok || p.PublishDate.IsZero() || !p.PublishDate.Before(end) {
+			continue
+		}
+		if target < 0 || p.PublishDate.After(items[target].(PostMetadata).PublishDate) {
+			target = i
+		}
+	}
+	return target
+}
+
+// extremeTarget returns the index of the newest dated item, or the oldest
+// when newest is false, whatever order the items are in. It's -1 when no item
+// has a date.
+func extremeTarget(items []list.Item, newest bool) int {
+	target := -1
+	for i, item := range items {
+		p, ok := item.(PostMetadata)
+		if !ok || p.PublishDate.IsZero() {
+			continue
+		}
+		if target < 0 {
+			target = i
+			continue
+		}
+		best := items[target].(PostMetadata).PublishDate
+		if newest && p.PublishDate.After(best) || !newest && p.PublishDate.Before(best) {
+			target = i
+		}
+	}
+	return target
+}
+
+// jumpToEnd moves the list cursor to the newest or oldest post and opens it.
+func (m *model) jumpToEnd(newest bool) tea.Cmd {
+	i := extremeTarget(m.postList.VisibleItems(), newest)
+	if i < 0 {
+		return m.setStatus("No dated posts")
+	}
+	return m.showPost(m.postList.VisibleItems()[i].(PostMetadata))
+}
+
+// jumpToDate moves the list cursor to the post on or before the date typed
+// into the jump prompt.
+func (m *model) jumpToDate(input string) tea.Cmd {
+	end, err := parseJumpDate(input)
+	if err != nil {
+		return m.setStatus(err.Error())
+	}
+	i := jumpTarget(m.postList.VisibleItems(), end)
+	if i < 0 {
+		return m.setStatus(fmt.Sprintf("No posts on or before %s", strings.TrimSpace(input)))
+	}
+	m.postList.Select(i)
+	p := m.postList.VisibleItems()[i].(PostMetadata)
+	return m.setStatus("Jumped to " + m.opts.formatDate(p.PublishDate))
+}
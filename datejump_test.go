@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// datedItems lists a post per date, in the order given, with zero times undated.
+func datedItems(dates ...time.Time) []list.Item {
+	items := make([]list.Item, len(dates))
+	for i, d := range dates {
+		items[i] = PostMetadata{PostTitle: d.Format(time.DateOnly), PublishDate: d}
+	}
+	return items
+}
+
+func TestJumpTarget(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 12, 0, 0, 0, time.UTC) }
+	newestFirst := datedItems(day(2024, 3, 10), day(2024, 1, 31), day(2023, 12, 1), day(2023, 6, 15), day(2022, 2, 2), time.Time{})
+	oldestFirst := datedItems(day(2022, 2, 2), day(2023, 6, 15), day(2023, 12, 1), day(2024, 1, 31), day(2024, 3, 10))
+	tests := []struct {
+		input string
+		items []list.Item
+		want  int
+	}{
+		{"2024-03-10", newestFirst, 0}, // The day itself counts
+		{"2024-03-09", newestFirst, 1},
+		{"2024-02", newestFirst, 1},
+		{"2024-01", newestFirst, 1},
+		{"2023", newestFirst, 2},
+		{"2023-06-14", newestFirst, 4},
+		{"2099", newestFirst, 0},
+		{"2022-02-01", newestFirst, -1},
+		{"2023", oldestFirst, 2},
+		{" 2024-01 ", oldestFirst, 3},
+	}
+	for _, tt := range tests {
+		end, err := parseJumpDate(tt.input)
+		if err != nil {
+			t.Errorf("parseJumpDate(%q): %v", tt.input, err)
+			continue
+		}
+		if got := jumpTarget(tt.items, end); got != tt.want {
+			t.Errorf("jumpTarget for %q = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "yesterday", "2024-13", "2024-02-30", "24", "2024/01"} {
+		if _, err := parseJumpDate(input); err == nil {
+			t.Errorf("parseJumpDate(%q) gave no error", input)
+		}
+	}
+}
+
+func TestExtremeTarget(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name                   string
+		items                  []list.Item
+		wantNewest, wantOldest int
+	}{
+		{"newest first", datedItems(day(2024, 3, 1), day(2023, 1, 1), day(2022, 5, 5)), 0, 2},
+		{"oldest first", datedItems(day(2022, 5, 5), day(2023, 1, 1), day(2024, 3, 1)), 2, 0},
+		{"shuffled with undated", datedItems(time.Time{}, day(2023, 1, 1), day(2024, 3, 1), time.Time{}, day(2022, 5, 5)), 2, 4},
+		{"only undated", datedItems(time.Time{}, time.Time{}), -1, -1},
+		{"empty", nil, -1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extremeTarget(tt.items, true); got != tt.wantNewest {
+				t.Errorf("newest: got %d, want %d", got, tt.wantNewest)
+			}
+			if got := extremeTarget(tt.items, false); got != tt.wantOldest {
+				t.Errorf("oldest: got %d, want %d", got, tt.wantOldest)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// --- Locale-aware dates ---
+
+var (
+	monthsShort = map[string][12]string{
+		"en": {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		"fr": {"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		"es": {"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		"de": {"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		"pt": {"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		"it": {"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		"nl": {"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+	}
+
+	// dateFormatters format a date for a language or a language_REGION, the
+	// region taking precedence.
+	dateFormatters = map[string]dateFormat{
+		"en": dayMonthYear("en", ""),
+		"en_US": func(t time.Time) string {
+			return fmt.Sprintf("%s %d, %d", monthsShort["en"][t.Month()-1], t.Day(), t.Year())
+		},
+		"fr": dayMonthYear("fr", ""),
+		"es": dayMonthYear("es", ""),
+		"de": dayMonthYear("de", "."),
+		"pt": dayMonthYear("pt", ""),
+		"it": dayMonthYear("it", ""),
+		"nl": dayMonthYear("nl", ""),
+		"ja": func(t time.Time) string { return fmt.Sprintf("%d年%d月%d日", t.Year(), t.Month(), t.Day()) },
+		"zh": func(t time.Time) string { return fmt.Sprintf("%d年%d月%d日", t.Year(), t.Month(), t.Day()) },
+		"ko": func(t time.Time) string { return fmt.Sprintf("%d년 %d월 %d일", t.Year(), t.Month(), t.Day()) },
+	}
+)
+
+// dayMonthYear formats dates like "15 Jan 2024", with an optional mark after
+// the day ("15. Jan. 2024" in German).
+func dayMonthYear(lang, dayMark string) dateFormat {
+	return func(t time.Time) string {
+		return fmt.Sprintf("%d%s %s %d", t.Day(), dayMark, monthsShort[lang][t.Month()-1], t.Year())
+	}
+}
+
+// dateFormat renders dates wherever they're shown.
+type dateFormat func(time.Time) string
+
+func isoDate(t time.Time) string { return t.Format("2006-01-02") }
+
+// localDateFormat picks the date format for a locale like "fr", "en_GB" or
+// "ja_JP.UTF-8"; an empty locale falls back to LC_ALL, LC_TIME and LANG.
+// Unknown locales, and C/POSIX, get ISO dates. It also returns the locale used
+// and whether it was recognized.
+func localDateFormat(locale string) (dateFormat, string, bool) {
+	if locale == "" {
+		for _, env := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+			if locale = os.Getenv(env); locale != "" {
+				break
+			}
+		}
+	}
+	locale, _, _ = strings.Cut(locale, ".") // Drop the encoding
+	locale = strings.ReplaceAll(locale, "-", "_")
+	lang, region, _ := strings.Cut(locale, "_")
+	lang = strings.ToLower(lang)
+
+	if f, ok := dateFormatters[lang+"_"+strings.ToUpper(region)]; ok {
+		return f, locale, true
+	}
+	if f, ok := dateFormatters[lang]; ok {
+		return f, locale, true
+	}
+	return isoDate, locale, locale == "" || locale == "C" || locale == "POSIX"
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalDateFormat(t *testing.T) {
+	for _, env := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		t.Setenv(env, "")
+	}
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		locale, want string
+		known        bool
+	}{
+		{"en_GB", "15 Jan 2024", true},
+		{"en_US.UTF-8", "Jan 15, 2024", true},
+		{"en-us", "Jan 15, 2024", true},
+		{"fr_FR", "15 janv. 2024", true},
+		{"de", "15. Jan. 2024", true},
+		{"ja_JP.UTF-8", "2024年1月15日", true},
+		{"ko", "2024년 1월 15일", true},
+		{"C", "2024-01-15", true},
+		{"POSIX", "2024-01-15", true},
+		{"", "2024-01-15", true},
+		{"xx_YY", "2024-01-15", false},
+	}
+	for _, tt := range tests {
+		formatDate, _, known := localDateFormat(tt.locale)
+		if got := formatDate(date); got != tt.want || known != tt.known {
+			t.Errorf("localDateFormat(%q): got %q, known %v; want %q, known %v", tt.locale, got, known, tt.want, tt.known)
+		}
+	}
+
+	// Without --locale, LC_ALL comes before LC_TIME and LANG
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	t.Setenv("LC_TIME", "de_DE.UTF-8")
+	if formatDate, locale, _ := localDateFormat(""); locale != "de_DE" || formatDate(date) != "15. Jan. 2024" {
+		t.Errorf("from LC_TIME: got %q, %q", locale, formatDate(date))
+	}
+	t.Setenv("LC_ALL", "ja_JP.UTF-8")
+	if formatDate, locale, _ := localDateFormat(""); locale != "ja_JP" || formatDate(date) != "2024年1月15日" {
+		t.Errorf("from LC_ALL: got %q, %q", locale, formatDate(date))
+	}
+}
+
+func TestLocalDatesShown(t *testing.T) {
+	formatDate, _, _ := localDateFormat("fr_FR")
+	post := PostMetadata{PostTitle: "Lancement", Slug: "lancement", PublishDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}
+	m := testModelWith(t, options{repo: defaultRepoConfig(), formatDate: formatDate}, post)
+	m.currentScreen = listScreen
+	if view := m.View(); !strings.Contains(view, "15 janv. 2024") {
+		t.Errorf("list doesn't show the French date:\n%s", view)
+	}
+	if block := m.metadataBlock(post, 80); !strings.Contains(block, "15 janv. 2024") {
+		t.Errorf("post header doesn't show the French date:\n%s", block)
+	}
+}
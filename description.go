@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+)
+
+// --- List item descriptions ---
+
+// defaultDescriptionTemplate reproduces the original "date | Cat: x | Tags: a, b" line.
+const defaultDescriptionTemplate = `{{.date}}{{with .category}} | Cat: {{.}}{{end}}{{with .tags}} | Tags: {{join . ", "}}{{end}}`
+
+// parseDescriptionTemplate parses a description template and renders it once
+// against an example post, so misspelled fields are caught at startup rather
+// than showing up in the list.
+func parseDescriptionTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("description").
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	example := PostMetadata{Category: "Events", Tags: []string{"go"}, Author: "someone", ReadingMinutes: 1}
+	if _, err := renderDescription(tmpl, example, isoDate); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// renderDescription fills tmpl with the fields a description can use, dates
+// written with formatDate.
+func renderDescription(tmpl *template.Template, p PostMetadata, formatDate dateFormat) (string, error) {
+	var b strings.Builder
+	date := "Undated"
+	if !p.PublishDate.IsZero() {
+		date = formatDate(p.PublishDate)
+	}
+	err := tmpl.Execute(&b, map[string]any{
+		"date":        date,
+		"category":    p.Category,
+		"tags":        p.Tags,
+		"author":      p.Author,
+		"readingTime": fmt.Sprintf("%d min read", p.ReadingMinutes),
+	})
+	return b.String(), err
+}
+
+// describedPost is a post in the list, described by the --description template.
+type describedPost struct {
+	PostMetadata
+	description *template.Template
+	formatDate  dateFormat
+}
+
+func (p describedPost) Description() string {
+	desc, err := renderDescription(p.description, p.PostMetadata, p.formatDate)
+	if err != nil {
+		log.Printf("Error rendering description for %s: %v", p.key(), err)
+	}
+	return desc
+}
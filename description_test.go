@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDescriptionTemplate(t *testing.T) {
+	post := PostMetadata{
+		PublishDate:    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Category:       "Events",
+		Tags:           []string{"go", "tui"},
+		Author:         "Jo",
+		ReadingMinutes: 4,
+	}
+	tests := []struct {
+		name, text string
+		post       PostMetadata
+		want       string
+		wantErr    bool
+	}{
+		{name: "fields", text: "{{.author}} · {{.readingTime}} · {{join .tags \"/\"}}", post: post, want: "Jo · 4 min read · go/tui"},
+		{name: "optional parts", text: "{{.date}}{{with .category}} | {{.}}{{end}}", post: PostMetadata{}, want: "Undated"},
+		{name: "misspelled field", text: "{{.catgory}}", wantErr: true},
+		{name: "unknown function", text: "{{upper .author}}", wantErr: true},
+		{name: "syntax error", text: "{{.author", wantErr: true},
+		{name: "bad use of a field", text: "{{join .author \", \"}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseDescriptionTemplate(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := renderDescription(tmpl, tt.post, isoDate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseDescriptionTemplate(defaultDescriptionTemplate); err != nil {
+		t.Errorf("the default template doesn't parse: %v", err)
+	}
+}
+
+func TestListDescription(t *testing.T) {
+	post := PostMetadata{PostTitle: "Launch", Slug: "launch", Author: "Jo", Category: "Events"}
+	tests := []struct {
+		description, want string
+	}{
+		{"", "Undated | Cat: Events"},
+		{"by {{.author}}", "by Jo"},
+	}
+	for _, tt := range tests {
+		m := testModelWith(t, options{repo: defaultRepoConfig(), description: tt.description}, post)
+		m.currentScreen = listScreen
+		if view := m.View(); !strings.Contains(view, tt.want) {
+			t.Errorf("with description %q the list doesn't show %q:\n%s", tt.description, tt.want, view)
+		}
+	}
+}
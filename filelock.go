@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// --- Locking files shared between sessions ---
+
+// withFileLock runs fn holding an exclusive lock on path, for reading,
+// changing and writing back a file that SSH sessions, and other processes
+// running bbs, share. The lock is taken on path+".lock", so path itself can
+// still be replaced with writeFileAtomic.
+func withFileLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
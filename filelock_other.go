@@ -0,0 +1,43 @@
+//go:build !unix
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockRetry = 20 * time.Millisecond
+	// lockTimeout is how long lockFile waits for another holder.
+	lockTimeout = 5 * time.Second
+	// staleLockAge is when a lock file is taken to be left over from a
+	// process that died holding it, since nothing holds one for long.
+	staleLockAge = 30 * time.Second
+)
+
+// lockFile takes the lock by creating the file at path, which must not exist
+// yet, and returns how to release it. Without flock, waiting means retrying.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("waiting for lock %s: timed out", path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
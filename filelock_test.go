@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithFileLockCounter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "counter")
+	const n = 50
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := withFileLock(path, func() error {
+				data, err := os.ReadFile(path)
+				if err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				count, _ := strconv.Atoi(string(data))
+				time.Sleep(time.Millisecond) // Gives a missing lock every chance to lose an increment
+				return writeFileAtomic(path, []byte(strconv.Itoa(count+1)))
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != strconv.Itoa(n) {
+		t.Errorf("counter is %s after %d increments", data, n)
+	}
+}
+
+func TestSaveStatsCmdConcurrent(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	const n = 20
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var s readingStats
+			s.recordVisit(time.Now())
+			s.recordRead(PostMetadata{PostTitle: "Post", Slug: fmt.Sprintf("post-%d", i)})
+			saveStatsCmd(fmt.Sprintf("user-%d", i), s)()
+		}()
+	}
+	wg.Wait()
+
+	for i := range n {
+		user := fmt.Sprintf("user-%d", i)
+		s := loadStats(user)
+		if _, ok := s.Reads[fmt.Sprintf("post-%d", i)]; !ok {
+			t.Errorf("%s's read of post-%d was lost: %+v", user, i, s)
+		}
+	}
+}
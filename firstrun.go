@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- First run ---
+
+// needsFirstRun reports whether to greet a new local user with the appearance
+// prompt. SSH visitors never see it, since their settings aren't kept.
+func needsFirstRun(opts options, prefs settings) bool {
+	return !opts.sshMode && !opts.noFirstRun && !prefs.FirstRunDone
+}
+
+// firstRunView is the one-time prompt offering the appearance settings before
+// the splash screen.
+func firstRunView(m model) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	valueStyle := lipgloss.NewStyle().Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	lines := []string{
+		titleStyle.Render("Welcome! Let's set up how posts look."),
+		"",
+		fmt.Sprintf("Theme         %s  %s", valueStyle.Render(m.glamourStyle()), hintStyle.Render("(T to change)")),
+		fmt.Sprintf("Reading size  %s  %s", valueStyle.Render(m.readingSize().String()), hintStyle.Render("(z to change)")),
+		"",
+		hintStyle.Render("Both can be changed later while reading. Press enter to save and continue."),
+	}
+	return lipgloss.NewStyle().
+		Border(uiBorder(lipgloss.RoundedBorder(), m.opts.asciiOnly)).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
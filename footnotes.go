@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Link modes and collapsed footnotes ---
+
+// linkMode is how links in posts are shown. Set from --links.
+type linkMode int
+
+const (
+	linksFootnotes linkMode = iota // Numbered, with the URLs listed at the end
+	linksInline                    // Left as glamour renders them
+	linksHidden                    // Just the link text
+)
+
+func (l linkMode) String() string {
+	switch l {
+	case linksInline:
+		return "inline"
+	case linksHidden:
+		return "hidden"
+	default:
+		return "footnotes"
+	}
+}
+
+// parseLinkMode parses a --links value.
+func parseLinkMode(s string) (linkMode, error) {
+	for l := linksFootnotes; l <= linksHidden; l++ {
+		if s == l.String() {
+			return l, nil
+		}
+	}
+	return linksFootnotes, fmt.Errorf("unknown link mode %q (want footnotes, inline or hidden)", s)
+}
+
+// apply rewrites markdown's links for the mode, returning their URLs too, in
+// order, except inline, where links stay as they are and none are collected.
+func (l linkMode) apply(markdown string) (string, []string) {
+	switch l {
+	case linksInline:
+		return markdown, nil
+	case linksHidden:
+		return hideLinks(markdown)
+	default:
+		return transformLinksToFootnotes(markdown)
+	}
+}
+
+// hideLinks replaces links with their text, and images with their alt text
+// as transformLinksToFootnotes shows it. Each URL is returned once.
+func hideLinks(markdown string) (string, []string) {
+	var urls []string
+	seen := make(map[string]bool)
+	hidden := markdownLinkRe.ReplaceAllStringFunc(markdown, func(match string) string {
+		submatches := markdownLinkRe.FindStringSubmatch(match)
+		text := submatches[2]
+		if submatches[1] == "!" {
+			text = `\[image\]`
+			if alt := strings.TrimSpace(submatches[2]); alt != "" {
+				text = fmt.Sprintf(`\[image: %s\]`, alt)
+			}
+		} else if text == "" {
+			return match
+		}
+		fields := strings.Fields(submatches[3])
+		if len(fields) == 0 {
+			return match // No URL to hide, as in [text]( )
+		}
+		if url := fields[0]; !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+		return text
+	})
+	return hidden, urls
+}
+
+// footnotesMarkerHint ends the marker standing in for a collapsed footnotes
+// section, like collapsedMarkerHint.
+const footnotesMarkerHint = "press f to show"
+
+// footnotesSection is the markdown appended to a post for its footnote URLs:
+// the numbered list when shown, or a one-line marker counting them.
+func footnotesSection(urls []string, shown bool) string {
+	if len(urls) == 0 {
+		return ""
+	}
+	var section strings.Builder
+	section.WriteString("\n\n---\n")
+	if !shown {
+		count := fmt.Sprintf("%d footnotes", len(urls))
+		if len(urls) == 1 {
+			count = "1 footnote"
+		}
+		section.WriteString("*\\[" + count + " — " + footnotesMarkerHint + "\\]*\n")
+		return section.String()
+	}
+	section.WriteString("**Footnotes:**\n")
+	for i, url := range urls {
+		section.WriteString(fmt.Sprintf("[%d]: %s\n", i+1, url))
+	}
+	return section.String()
+}
+
+// toggleFootnotes shows or collapses the selected post's footnotes section,
+// keeping the reader's place.
+func (m *model) toggleFootnotes() {
+	if m.selectedPost == nil || m.showRaw || m.opts.links != linksFootnotes || len(m.footnoteURLs) == 0 {
+		return
+	}
+	postKey := m.selectedPost.key()
+	m.shownFootnotes[postKey] = !m.shownFootnotes[postKey]
+	offset := m.viewport.YOffset
+	m.setViewportContent()
+	if !m.follow {
+		m.viewport.SetYOffset(offset)
+	}
+}
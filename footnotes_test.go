@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFootnotesMarkerCount(t *testing.T) {
+	tests := []struct {
+		name, content, marker string
+		footnotes             int
+	}{
+		{"one", "See [the pad](https://pad.example).", "[1 footnote — press f to show]", 1},
+		{
+			"several with an image",
+			"See [the pad](https://pad.example), [the crew](https://crew.example) and ![the plume](https://plume.example/p.png).",
+			"[3 footnotes — press f to show]", 3,
+		},
+		{"none", "No links here.", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := PostMetadata{PostTitle: "Launch", Slug: "launch", Content: tt.content}
+			m := testModel(t, post)
+			m.showPost(post)
+			if len(m.footnoteURLs) != tt.footnotes {
+				t.Fatalf("got %d footnotes, want %d", len(m.footnoteURLs), tt.footnotes)
+			}
+			collapsed := ansi.Strip(m.renderedContent)
+			if tt.footnotes == 0 {
+				if strings.Contains(collapsed, footnotesMarkerHint) {
+					t.Errorf("marker without footnotes:\n%s", collapsed)
+				}
+				return
+			}
+			if !strings.Contains(collapsed, tt.marker) {
+				t.Errorf("no marker %q in:\n%s", tt.marker, collapsed)
+			}
+
+			m.toggleFootnotes()
+			shown := strings.Join(strings.Fields(ansi.Strip(m.renderedContent)), " ") // Footnotes may wrap
+			if strings.Contains(shown, footnotesMarkerHint) {
+				t.Errorf("marker still shown with the footnotes:\n%s", shown)
+			}
+			for i, url := range m.footnoteURLs {
+				if !strings.Contains(shown, fmt.Sprintf("[%d]: %s", i+1, url)) {
+					t.Errorf("footnote %d, %s, missing from:\n%s", i+1, url, shown)
+				}
+			}
+			if strings.Contains(shown, fmt.Sprintf("[%d]: ", tt.footnotes+1)) {
+				t.Errorf("more footnotes listed than the marker counted:\n%s", shown)
+			}
+		})
+	}
+}
+
+func TestHideLinks(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+		urls           []string
+	}{
+		{"link", "See [the site](https://x.example)", "See the site", []string{"https://x.example"}},
+		{"image with a title", `![Logo](https://x.example/logo.svg "The logo")`, `\[image: Logo\]`, []string{"https://x.example/logo.svg"}},
+		{"repeated URL", "[a](https://x.example) [b](https://x.example)", "a b", []string{"https://x.example"}},
+		{"blank link URL", "[text]( )", "[text]( )", nil},
+		{"blank image URL", "![alt]( )", "![alt]( )", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, urls := hideLinks(tt.in)
+			if got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+			if !slices.Equal(urls, tt.urls) {
+				t.Errorf("got URLs %q, want %q", urls, tt.urls)
+			}
+		})
+	}
+}
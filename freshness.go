@@ -0,0 +1,88 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Freshness badge ---
+
+// freshnessAges are how long after its last update a post counts as fresh,
+// then recent; anything older is archived. Set from --fresh-days and
+// --recent-days.
+type freshnessAges struct {
+	fresh, recent time.Duration
+}
+
+var defaultFreshness = freshnessAges{fresh: 30 * 24 * time.Hour, recent: 365 * 24 * time.Hour}
+
+type freshness int
+
+const (
+	freshnessFresh freshness = iota
+	freshnessRecent
+	freshnessArchived
+)
+
+func (f freshness) String() string {
+	switch f {
+	case freshnessFresh:
+		return "fresh"
+	case freshnessRecent:
+		return "recent"
+	default:
+		return "archived"
+	}
+}
+
+// color is the badge background: green, amber, then grey as a post ages.
+func (f freshness) color() lipgloss.Color {
+	switch f {
+	case freshnessFresh:
+		return lipgloss.Color("42")
+	case freshnessRecent:
+		return lipgloss.Color("214")
+	default:
+		return lipgloss.Color("244")
+	}
+}
+
+// lastUpdated is when the post last changed: its update date, or its publish
+// date when it was never updated.
+func (p PostMetadata) lastUpdated() time.Time {
+	if p.UpdateDate.After(p.PublishDate) {
+		return p.UpdateDate
+	}
+	return p.PublishDate
+}
+
+// postFreshness grades the post by its age at now. A post without dates has
+// no freshness.
+func postFreshness(p PostMetadata, now time.Time, ages freshnessAges) (freshness, bool) {
+	updated := p.lastUpdated()
+	if updated.IsZero() {
+		return 0, false
+	}
+	switch age := now.Sub(updated); {
+	case age < ages.fresh:
+		return freshnessFresh, true
+	case age < ages.recent:
+		return freshnessRecent, true
+	default:
+		return freshnessArchived, true
+	}
+}
+
+// freshnessBadge renders the post's freshness as a colored tag, or "" if it has none.
+func freshnessBadge(p PostMetadata, now time.Time, ages freshnessAges) string {
+	f, ok := postFreshness(p, now, ages)
+	if !ok {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(f.color()).
+		Padding(0, 1).
+		Render(f.String())
+}
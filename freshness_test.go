@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostFreshness(t *testing.T) {
+	ages := freshnessAges{fresh: 30 * 24 * time.Hour, recent: 365 * 24 * time.Hour}
+	freshFor, recentFor := ages.fresh, ages.recent
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	tests := []struct {
+		name   string
+		post   PostMetadata
+		want   freshness
+		wantOK bool
+	}{
+		{"no dates", PostMetadata{}, 0, false},
+		{"just published", PostMetadata{PublishDate: now}, freshnessFresh, true},
+		{"just under fresh", PostMetadata{PublishDate: ago(freshFor - time.Second)}, freshnessFresh, true},
+		{"exactly fresh", PostMetadata{PublishDate: ago(freshFor)}, freshnessRecent, true},
+		{"just under recent", PostMetadata{PublishDate: ago(recentFor - time.Second)}, freshnessRecent, true},
+		{"exactly recent", PostMetadata{PublishDate: ago(recentFor)}, freshnessArchived, true},
+		{"updated later", PostMetadata{PublishDate: ago(2 * recentFor), UpdateDate: ago(24 * time.Hour)}, freshnessFresh, true},
+		{"update before publish", PostMetadata{PublishDate: ago(recentFor), UpdateDate: ago(2 * recentFor)}, freshnessArchived, true},
+		{"only updated", PostMetadata{UpdateDate: ago(40 * 24 * time.Hour)}, freshnessRecent, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := postFreshness(tt.post, now, ages)
+			if ok != tt.wantOK || ok && got != tt.want {
+				t.Errorf("got %s, %t; want %s, %t", got, ok, tt.want, tt.wantOK)
+			}
+			if badge := freshnessBadge(tt.post, now, ages); (badge != "") != tt.wantOK {
+				t.Errorf("got badge %q", badge)
+			}
+		})
+	}
+}
+
+func TestFreshnessBadgeInHeader(t *testing.T) {
+	var content strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&content, "Paragraph %d.\n\n", i)
+	}
+	post := PostMetadata{PostTitle: "Launch", Slug: "launch", PublishDate: time.Now(), Content: content.String()}
+	m := testModel(t, post)
+	m.showPost(post)
+	m.viewport.GotoBottom()
+	if !strings.Contains(m.headerView(), "fresh") {
+		t.Errorf("header has no badge: %q", m.headerView())
+	}
+	if view := m.View(); !strings.Contains(view, "fresh") {
+		t.Errorf("badge scrolled away:\n%s", view)
+	}
+	if strings.Contains(m.metadataBlock(post, 80), "fresh") {
+		t.Error("badge also in the scrolling metadata")
+	}
+}
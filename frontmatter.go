@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// --- TOML and JSON frontmatter ---
+
+// frontmatterFormat is the language of a post's frontmatter, told by its
+// opening fence: --- for YAML, +++ for TOML, or a JSON object's {.
+type frontmatterFormat int
+
+const (
+	yamlFrontmatter frontmatterFormat = iota
+	tomlFrontmatter
+	jsonFrontmatter
+)
+
+func (f frontmatterFormat) String() string {
+	switch f {
+	case tomlFrontmatter:
+		return "TOML"
+	case jsonFrontmatter:
+		return "JSON"
+	default:
+		return "YAML"
+	}
+}
+
+// decodeFrontmatter fills meta from front, which starts on line firstLine of
+// the file. Each format decodes straight into PostMetadata by its own tags,
+// and the dates then go through setDates alike.
+func decodeFrontmatter(format frontmatterFormat, front string, firstLine int, meta *PostMetadata) error {
+	var err error
+	switch format {
+	case tomlFrontmatter:
+		post := datedPost{postFields: postFields(*meta)}
+		if _, err = toml.Decode(front, &post); err == nil {
+			post.apply(meta)
+		}
+	case jsonFrontmatter:
+		post := datedPost{postFields: postFields(*meta)}
+		if err = json.Unmarshal([]byte(front), &post); err == nil {
+			derived := *meta
+			post.apply(meta)
+			meta.keepDerived(derived)
+		}
+	default:
+		err = yaml.Unmarshal([]byte(front), meta)
+	}
+	if err != nil {
+		// Decoders count lines from the start of the frontmatter, not the file
+		return fmt.Errorf("%s", yamlErrorAt(err, firstLine))
+	}
+	return nil
+}
+
+type postFields PostMetadata // PostMetadata's fields without its methods
+
+// datedPost decodes TOML or JSON frontmatter with the dates as the decoder
+// finds them, for setDates.
+type datedPost struct {
+	postFields
+	PublishDate any `toml:"publishDate" json:"publishDate"`
+	UpdateDate  any `toml:"updateDate" json:"updateDate"`
+}
+
+func (d datedPost) apply(meta *PostMetadata) {
+	*meta = PostMetadata(d.postFields)
+	meta.setDates(d.PublishDate, d.UpdateDate)
+}
+
+// keepDerived puts back the fields of from that are worked out rather than
+// written in frontmatter. encoding/json fills untagged fields by name in any
+// case, so JSON keys such as "plainText" would otherwise set them.
+func (p *PostMetadata) keepDerived(from PostMetadata) {
+	p.Content = from.Content
+	p.ReadingMinutes = from.ReadingMinutes
+	p.SourceURL = from.SourceURL
+	p.SourcePath = from.SourcePath
+	p.ListTitle = from.ListTitle
+	p.SuggestedTags = from.SuggestedTags
+	p.PlainText = from.PlainText
+}
+
+// UnmarshalYAML decodes frontmatter as the yaml tags say, with the dates
+// through setDates.
+func (p *PostMetadata) UnmarshalYAML(node *yaml.Node) error {
+	var publish, update any
+	if node.Kind == yaml.MappingNode {
+		kept := *node
+		kept.Content = nil
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			switch k.Value {
+			case "publishDate":
+				v.Decode(&publish) // Anything decodes to any
+			case "updateDate":
+				v.Decode(&update)
+			default:
+				kept.Content = append(kept.Content, k, v)
+			}
+		}
+		node = &kept
+	}
+	if err := node.Decode((*postFields)(p)); err != nil {
+		return err
+	}
+	p.setDates(publish, update)
+	return nil
+}
+
+// setDates sets the post's dates from the values its frontmatter gave, nil
+// where it had none. One that isn't a date is left out with a warning rather
+// than failing the whole post; without its date a post is undated.
+func (p *PostMetadata) setDates(publish, update any) {
+	for _, d := range []struct {
+		name  string
+		value any
+		field *time.Time
+	}{{"publishDate", publish, &p.PublishDate}, {"updateDate", update, &p.UpdateDate}} {
+		if d.value == nil {
+			continue
+		}
+		t, ok := frontmatterDate(d.value)
+		if !ok {
+			log.Printf("Ignoring %s %v of %q, which isn't a date", d.name, d.value, p.PostTitle)
+		}
+		*d.field = t
+	}
+}
+
+// frontmatterTimeLayouts are the date and time forms taken for dates, the
+// ones YAML and TOML have.
+var frontmatterTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+}
+
+// frontmatterDate makes a time of a date as a decoder gives it: a TOML date
+// or datetime, or a string in one of frontmatterTimeLayouts, as YAML and JSON
+// give them. A date or time without a zone is taken as UTC in every format.
+func frontmatterDate(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		if zone := v.Location().String(); zone == "date-local" || zone == "datetime-local" {
+			// TOML's local dates come in the machine's zone; the other formats use UTC
+			return time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC), true
+		}
+		return v, true
+	case string:
+		for _, layout := range frontmatterTimeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
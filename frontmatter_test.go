@@ -0,0 +1,197 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeFrontmatterFormats(t *testing.T) {
+	want := PostMetadata{
+		PostTitle:      "Launch Day",
+		Excerpt:        "Watching from the causeway",
+		PublishDate:    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		UpdateDate:     time.Date(2024, 5, 2, 13, 30, 0, 0, time.UTC),
+		Category:       "Space",
+		Author:         "Jo",
+		Tags:           []string{"rockets", "florida"},
+		Slug:           "launch-day",
+		Aliases:        []string{"launch"},
+		Dir:            "rtl",
+		Private:        true,
+		Content:        "Liftoff!",
+		ReadingMinutes: 1,
+		PlainText:      "Liftoff!",
+	}
+	tests := []struct {
+		name, post string
+	}{
+		{"YAML", `---
+title: Launch Day
+excerpt: Watching from the causeway
+publishDate: 2024-05-01
+updateDate: 2024-05-02T13:30:00Z
+category: Space
+author: Jo
+tags: [rockets, florida]
+slug: launch-day
+aliases:
+  - launch
+private: true
+dir: rtl
+---
+Liftoff!
+`},
+		{"TOML", `+++
+title = "Launch Day"
+excerpt = 'Watching from the causeway'
+publishDate = 2024-05-01
+updateDate = 2024-05-02T13:30:00Z
+category = "Space"
+author = "Jo"
+tags = ["rockets", "florida"]
+slug = "launch-day"
+aliases = [
+  "launch", # Its first slug
+]
+private = true
+dir = "rtl"
+
+[[links]] # Arrays of tables are TOML too, even ones PostMetadata has no use for
+url = "https://example.com"
++++
+Liftoff!
+`},
+		{"TOML local datetime", `+++
+title = "Launch Day"
+excerpt = "Watching from the causeway"
+publishDate = "2024-05-01"
+updateDate = 2024-05-02T13:30:00
+category = "Space"
+author = "Jo"
+tags = ["rockets", "florida"]
+slug = "launch-day"
+aliases = ["launch"]
+private = true
+dir = "rtl"
++++
+Liftoff!
+`},
+		{"JSON", `{
+  "title": "Launch Day",
+  "excerpt": "Watching from the causeway",
+  "publishDate": "2024-05-01",
+  "updateDate": "2024-05-02T13:30:00Z",
+  "category": "Space",
+  "author": "Jo",
+  "tags": ["rockets", "florida"],
+  "slug": "launch-day",
+  "aliases": ["launch"],
+  "private": true,
+  "dir": "rtl"
+}
+Liftoff!
+`},
+		{"JSON with derived keys", `{
+  "title": "Launch Day",
+  "excerpt": "Watching from the causeway",
+  "publishDate": "2024-05-01",
+  "updateDate": "2024-05-02T13:30:00Z",
+  "category": "Space",
+  "author": "Jo",
+  "tags": ["rockets", "florida"],
+  "slug": "launch-day",
+  "aliases": ["launch"],
+  "private": true,
+  "dir": "rtl",
+  "listTitle": "Launch Day (again)",
+  "suggestedTags": ["made-up"],
+  "plainText": "Not the text",
+  "readingMinutes": 60,
+  "sourceURL": "https://elsewhere.example/post.md",
+  "content": "Not the body"
+}
+Liftoff!
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePost(tt.name, []byte(tt.post))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got  %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestTOMLErrorLines(t *testing.T) {
+	tests := []struct {
+		name, post, want string
+	}{
+		{"missing =", "\n+++\ntitle = 'ok'\nbad\n+++\n", "toml: line 4: expected '.' or '='"},
+		{"unterminated array", "+++\ntitle = 'ok'\n\ntags = [\"a\",\n+++\n", `toml: line 4 (last key "tags"): unexpected EOF`},
+		{"bad value", "<!-- draft -->\n+++\nslug = what\n+++\n", `toml: line 3 (last key "slug"): expected value but found "what"`},
+		{"duplicate key", "+++\nslug = 'a'\nslug = 'b'\n+++\n", `toml: line 3 (last key "slug"): Key 'slug' has already been defined`},
+		{"wrong type", "+++\ntitle = 'ok'\ntags = 'one'\n+++\n", `toml: line 3 (last key "tags"): incompatible types`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePost("post.mdx", []byte(tt.post))
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndatedPosts(t *testing.T) {
+	tests := []struct {
+		name, post string
+	}{
+		{"missing publishDate", "---\ntitle: Undated\n---\nBody\n"},
+		{"unparseable publishDate", "---\ntitle: Undated\npublishDate: sometime in May\nupdateDate: [2024]\n---\nBody\n"},
+		{"unparseable TOML publishDate", "+++\ntitle = 'Undated'\npublishDate = 'sometime in May'\nupdateDate = [2024]\n+++\nBody\n"},
+		{"unparseable JSON publishDate", "{\"title\": \"Undated\", \"publishDate\": \"sometime in May\", \"updateDate\": 2024}\nBody\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			undated, err := parsePost("post.mdx", []byte(tt.post))
+			if err != nil {
+				t.Fatalf("post was dropped: %v", err)
+			}
+			if undated.PostTitle != "Undated" || !undated.PublishDate.IsZero() || !undated.UpdateDate.IsZero() {
+				t.Fatalf("got %q published %s, updated %s; want it undated", undated.PostTitle, undated.PublishDate, undated.UpdateDate)
+			}
+
+			older := PostMetadata{PostTitle: "Older", PublishDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+			newer := PostMetadata{PostTitle: "Newer", PublishDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+			for mode, want := range map[sortMode][]string{
+				sortNewest: {"Newer", "Older", "Undated"},
+				sortOldest: {"Older", "Newer", "Undated"},
+			} {
+				posts := []PostMetadata{undated, older, newer}
+				sortPosts(posts, mode)
+				var got []string
+				for _, p := range posts {
+					got = append(got, p.PostTitle)
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("sorted %s: got %q, want %q", mode, got, want)
+				}
+			}
+
+			// The zero time falls on 1 January
+			if got := onThisDay([]PostMetadata{undated}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); len(got) != 0 {
+				t.Errorf("onThisDay listed the undated post: %+v", got)
+			}
+		})
+	}
+}
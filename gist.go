@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- GitHub Gist Fetching Logic ---
+const githubAPIGistURLFormat = "%s/gists/%s"
+
+// GitHubGist is the subset of the Gists API response we use.
+type GitHubGist struct {
+	HTMLURL   string                    `json:"html_url"`
+	CreatedAt time.Time                 `json:"created_at"`
+	Files     map[string]GitHubGistFile `json:"files"`
+	Owner     struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// GitHubGistFile is one file of a Gist. Content is inlined by the API unless Truncated is set.
+type GitHubGistFile struct {
+	Filename  string `json:"filename"`
+	RawURL    string `json:"raw_url"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated"`
+}
+
+// fetchGistCmd loads every .md/.mdx file in a Gist as a post. Files may carry
+// frontmatter like repo posts; anything it leaves out is filled from the Gist.
+// apiBase is the GitHub API the Gist is read from.
+func fetchGistCmd(apiBase, gistID string, client fetchClient, progress progressFunc) tea.Cmd {
+	return func() tea.Msg {
+		apiURL := fmt.Sprintf(githubAPIGistURLFormat, apiBase, gistID)
+
+		apiBody, err := httpGet(client, apiURL)
+		if err != nil {
+			log.Println(err)
+			return postsLoadedMsg{posts: nil, err: err}
+		}
+
+		var gist GitHubGist
+		if err := json.Unmarshal(apiBody, &gist); err != nil {
+			errMsg := fmt.Errorf("unmarshalling Gist JSON from %s: %w", apiURL, err)
+			log.Println(errMsg)
+			return postsLoadedMsg{posts: nil, err: errMsg}
+		}
+
+		posts, firstError := parseGistFiles(client, gist, progress)
+		posts = dedupePosts(posts)
+
+		sort.SliceStable(posts, func(i, j int) bool {
+			return posts[i].PublishDate.After(posts[j].PublishDate)
+		})
+
+		if len(posts) == 0 {
+			if firstError == nil {
+				firstError = fmt.Errorf("no .md or .mdx files in gist %s", gistID)
+			}
+			return postsLoadedMsg{posts: nil, err: fmt.Errorf("failed to load any posts, first error: %w", firstError)}
+		}
+		return postsLoadedMsg{posts: posts, err: nil}
+	}
+}
+
+// parseGistFiles turns the markdown files of a Gist into posts, fetching any
+// content the API truncated. It returns the first per-file error alongside the
+// posts that did load. progress is told of each file done out of all of them.
+func parseGistFiles(client fetchClient, gist GitHubGist, progress progressFunc) ([]PostMetadata, error) {
+	var posts []PostMetadata
+	var firstError error
+
+	// Visit files by name so posts sharing the Gist's date keep a stable order
+	names := make([]string, 0, len(gist.Files))
+	for name, file := range gist.Files {
+		if ext := path.Ext(file.Filename); ext == ".md" || ext == ".mdx" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	defer progress.report(len(names), len(names))
+
+	for i, name := range names {
+		progress.report(i, len(names))
+		file := gist.Files[name]
+		ext := path.Ext(file.Filename)
+
+		body := []byte(file.Content)
+		if file.Truncated {
+			var err error
+			body, err = httpGet(client, file.RawURL)
+			if err != nil {
+				log.Println(err)
+				if firstError == nil {
+					firstError = err
+				}
+				continue
+			}
+		}
+
+		meta, err := parsePost(file.Filename, body)
+		if errors.Is(err, errNoFrontmatter) {
+			// Plain markdown is fine for a Gist, its metadata fills the gaps
+			meta = PostMetadata{Content: strings.TrimSpace(string(body))}
+			meta.ReadingMinutes = readingMinutes(meta.Content)
+			meta.PlainText = plainText(meta.Content)
+		} else if err != nil {
+			log.Println(err)
+			if firstError == nil {
+				firstError = err
+			}
+			continue
+		}
+
+		base := strings.TrimSuffix(file.Filename, ext)
+		if meta.PostTitle == "" {
+			meta.PostTitle = base
+		}
+		if meta.Slug == "" {
+			meta.Slug = base
+		}
+		if meta.PublishDate.IsZero() {
+			meta.PublishDate = gist.CreatedAt
+		}
+		if meta.Author == "" {
+			meta.Author = gist.Owner.Login
+		}
+		meta.SourceURL = file.RawURL
+		meta.SourcePath = file.Filename
+		posts = append(posts, meta)
+	}
+	return posts, firstError
+}
+
+// httpGet fetches url and returns the body, treating any non-200 status as an error.
+func httpGet(client fetchClient, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for %s: %w", url, err)
+	}
+	authorizeGitHub(req)
+
+	resp, err := doWithRetry(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if err := rateLimitError(resp); err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading body for %s: %w", url, err)
+	}
+	return body, nil
+}
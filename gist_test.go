@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGistFiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/big.md" {
+			fmt.Fprint(w, "---\ntitle: Big\n---\nAll of it")
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	gist := GitHubGist{CreatedAt: created, Files: map[string]GitHubGistFile{
+		"notes.md":  {Filename: "notes.md", RawURL: "raw/notes.md", Content: "Just markdown"},
+		"post.mdx":  {Filename: "post.mdx", RawURL: "raw/post.mdx", Content: "---\ntitle: Post\nslug: the-post\nauthor: Jo\npublishDate: 2023-01-02\n---\nBody"},
+		"big.md":    {Filename: "big.md", RawURL: srv.URL + "/big.md", Content: "---\ntitle: Big\n---\nAll", Truncated: true},
+		"gone.md":   {Filename: "gone.md", RawURL: srv.URL + "/gone.md", Truncated: true},
+		"broken.md": {Filename: "broken.md", Content: "---\ntitle: [\n---\n"},
+		"script.go": {Filename: "script.go", Content: "package main"},
+	}}
+	gist.Owner.Login = "octocat"
+
+	var reports []string
+	progress := progressFunc(func(loaded, total int) { reports = append(reports, fmt.Sprintf("%d/%d", loaded, total)) })
+	posts, err := parseGistFiles(testClient(0), gist, progress)
+	if err == nil || !strings.Contains(err.Error(), "broken.md") {
+		t.Errorf("got error %v, want broken.md's, the first file by name to fail", err)
+	}
+
+	want := []PostMetadata{
+		{PostTitle: "Big", Slug: "big", Author: "octocat", PublishDate: created, Content: "All of it", PlainText: "All of it", ReadingMinutes: 1, SourceURL: srv.URL + "/big.md", SourcePath: "big.md"},
+		{PostTitle: "notes", Slug: "notes", Author: "octocat", PublishDate: created, Content: "Just markdown", PlainText: "Just markdown", ReadingMinutes: 1, SourceURL: "raw/notes.md", SourcePath: "notes.md"},
+		{PostTitle: "Post", Slug: "the-post", Author: "Jo", PublishDate: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Content: "Body", PlainText: "Body", ReadingMinutes: 1, SourceURL: "raw/post.mdx", SourcePath: "post.mdx"},
+	}
+	if len(posts) != len(want) {
+		t.Fatalf("got %d posts %q, want %q", len(posts), titles(posts), titles(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(posts[i], want[i]) {
+			t.Errorf("post %d:\n got %+v\nwant %+v", i, posts[i], want[i])
+		}
+	}
+	if got := strings.Join(reports, " "); got != "0/5 1/5 2/5 3/5 4/5 5/5" {
+		t.Errorf("got progress %s", got)
+	}
+}
+
+func TestFetchGist(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gists/abc":
+			fmt.Fprintf(w, `{
+				"html_url": "https://gist.github.com/abc",
+				"created_at": "2024-06-01T12:00:00Z",
+				"owner": {"login": "octocat"},
+				"files": {
+					"old.md": {"filename": "old.md", "raw_url": "%[1]s/raw/old.md", "content": "---\ntitle: Old\npublishDate: 2023-01-02\n---\nFirst", "truncated": false},
+					"big.md": {"filename": "big.md", "raw_url": "%[1]s/raw/big.md", "content": "---\ntitle: Big\n---\nAll", "truncated": true},
+					"run.sh": {"filename": "run.sh", "raw_url": "%[1]s/raw/run.sh", "content": "echo hi", "truncated": false}
+				}
+			}`, srv.URL)
+		case "/gists/bad":
+			fmt.Fprint(w, `{"files": [`)
+		case "/gists/code":
+			fmt.Fprintf(w, `{"files": {"run.sh": {"filename": "run.sh", "raw_url": "%s/raw/run.sh", "content": "echo hi"}}}`, srv.URL)
+		case "/raw/big.md":
+			fmt.Fprint(w, "---\ntitle: Big\n---\nAll of it")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	msg := fetchGistCmd(srv.URL, "abc", testClient(0), nil)().(postsLoadedMsg)
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	want := []PostMetadata{
+		{PostTitle: "Big", Slug: "big", Author: "octocat", PublishDate: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), Content: "All of it", PlainText: "All of it", ReadingMinutes: 1, SourceURL: srv.URL + "/raw/big.md", SourcePath: "big.md"},
+		{PostTitle: "Old", Slug: "old", Author: "octocat", PublishDate: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Content: "First", PlainText: "First", ReadingMinutes: 1, SourceURL: srv.URL + "/raw/old.md", SourcePath: "old.md"},
+	}
+	if !reflect.DeepEqual(msg.posts, want) {
+		t.Errorf("got\n%+v\nwant\n%+v", msg.posts, want)
+	}
+
+	for _, tt := range []struct{ id, err string }{
+		{"missing", "404"},
+		{"bad", "unmarshalling Gist JSON"},
+		{"code", "no .md or .mdx files in gist code"},
+	} {
+		msg := fetchGistCmd(srv.URL, tt.id, testClient(0), nil)().(postsLoadedMsg)
+		if msg.err == nil || !strings.Contains(msg.err.Error(), tt.err) || msg.posts != nil {
+			t.Errorf("gist %s: got %d posts, error %v; want an error containing %q", tt.id, len(msg.posts), msg.err, tt.err)
+		}
+	}
+}
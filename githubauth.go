@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- GitHub authentication and rate limits ---
+
+// errRateLimited is returned when GitHub refuses a request because the hourly
+// quota is used up: 60 requests unauthenticated, 5000 with GITHUB_TOKEN.
+var errRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// authorizeGitHub adds the GITHUB_TOKEN from the environment, if set, to a
+// request bound for GitHub. Other hosts never see the token.
+func authorizeGitHub(req *http.Request) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" || !isGitHubHost(req.URL.Hostname()) {
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// isGitHubHost reports whether host is GitHub's, including the API and
+// raw.githubusercontent.com and gist.githubusercontent.com downloads.
+func isGitHubHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "github.com" || strings.HasSuffix(host, ".github.com") || strings.HasSuffix(host, ".githubusercontent.com")
+}
+
+// rateLimitError explains a response refused for the rate limit, saying when
+// the quota resets, or returns nil for any other response.
+func rateLimitError(resp *http.Response) error {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+	hint := ""
+	if os.Getenv("GITHUB_TOKEN") == "" {
+		hint = "; set GITHUB_TOKEN for a higher limit"
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w%s", errRateLimited, hint)
+	}
+	at := time.Unix(reset, 0)
+	minutes := max(0, int((time.Until(at)+time.Minute-1)/time.Minute))
+	return fmt.Errorf("%w, resets at %s (in %d min)%s", errRateLimited, at.Format("15:04 MST"), minutes, hint)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	ssh "github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// --- Farewell message ---
+
+// goodbyeMiddleware prints the --goodbye message once a session's UI has
+// exited, after the alternate screen is left, so it stays in the reader's
+// scrollback as the connection closes.
+func goodbyeMiddleware(message string) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			next(sess)
+			if message != "" {
+				wish.Println(sess, message)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Post history ---
+
+// maxHistory caps how many posts back navigation remembers.
+const maxHistory = 50
+
+// historyEntry is a post the reader left, and how far down it they were.
+type historyEntry struct {
+	key     string
+	yOffset int
+}
+
+// postHistory is a browser-style history of the posts viewed this session.
+type postHistory struct {
+	back    []historyEntry
+	forward []historyEntry
+}
+
+// push adds e to stack, dropping the oldest entry beyond maxHistory.
+func push(stack []historyEntry, e historyEntry) []historyEntry {
+	stack = append(stack, e)
+	if len(stack) > maxHistory {
+		stack = stack[len(stack)-maxHistory:]
+	}
+	return stack
+}
+
+// current is the entry to come back to for the post on screen now.
+func (m model) current() historyEntry {
+	return historyEntry{key: m.selectedPost.key(), yOffset: m.viewport.YOffset}
+}
+
+// postByKey finds a loaded post, or the front page, by its key.
+func (m model) postByKey(key string) (PostMetadata, bool) {
+	if m.opts.homePage != nil && m.opts.homePage.key() == key {
+		return *m.opts.homePage, true
+	}
+	for _, p := range m.posts {
+		if p.key() == key {
+			return p, true
+		}
+	}
+	return PostMetadata{}, false
+}
+
+// openPost selects p as a new step of the history, so back returns to the
+// post being left. Like a browser, it forgets the posts ahead.
+func (m *model) openPost(p PostMetadata) tea.Cmd {
+	if m.selectedPost != nil && m.selectedPost.key() != p.key() {
+		m.history.back = push(m.history.back, m.current())
+		m.history.forward = nil
+	}
+	return tea.Batch(m.selectPost(p), m.recordRead(p))
+}
+
+// historyBack returns to the previous post at the position it was left.
+func (m *model) historyBack() tea.Cmd {
+	return m.historyStep(&m.history.back, &m.history.forward, "No earlier post")
+}
+
+// historyForward undoes a historyBack.
+func (m *model) historyForward() tea.Cmd {
+	return m.historyStep(&m.history.forward, &m.history.back, "No later post")
+}
+
+// historyStep pops the post to show from from, remembering the current one on
+// to. Posts that are no longer loaded are skipped.
+func (m *model) historyStep(from, to *[]historyEntry, empty string) tea.Cmd {
+	for len(*from) > 0 {
+		e := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		p, ok := m.postByKey(e.key)
+		if !ok {
+			continue
+		}
+		if m.selectedPost != nil {
+			*to = push(*to, m.current())
+		}
+		cmd := m.selectPost(p)
+		if !m.follow {
+			m.viewport.SetYOffset(e.yOffset)
+		}
+		return cmd
+	}
+	return m.setStatus(empty)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// --- Copy as HTML ---
+
+// postMarkdown is goldmark with the GitHub extensions posts are written for:
+// tables, task lists, strikethrough, autolinks, and footnotes.
+var postMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM, extension.Footnote))
+
+// postHTML converts a post to an HTML fragment for pasting into rich text
+// editors: its title as a heading, then its content. Raw HTML in the post is
+// left out, as goldmark does by default.
+func postHTML(p PostMetadata) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("<h1>" + html.EscapeString(p.PostTitle) + "</h1>\n")
+	if err := postMarkdown.Convert([]byte(stripTags(p.Content)), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
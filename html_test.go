@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestPostHTML(t *testing.T) {
+	post := PostMetadata{
+		PostTitle: "Launch <Day> & more",
+		Content: "import Chart from './chart'\n\n" +
+			"Watch from [the causeway](https://example.com/causeway \"Causeway\").\n\n" +
+			"<Callout type=\"warn\">Bring **water**.</Callout>\n\n" +
+			"```go\nfmt.Println(\"<liftoff>\")\n```\n\n" +
+			"| Pad | Rocket |\n|---|---|\n| 39A | Falcon |\n",
+	}
+	got, err := postHTML(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<h1>Launch &lt;Day&gt; &amp; more</h1>\n" +
+		"<p>Watch from <a href=\"https://example.com/causeway\" title=\"Causeway\">the causeway</a>.</p>\n" +
+		"<p>Bring <strong>water</strong>.</p>\n" +
+		"<pre><code class=\"language-go\">fmt.Println(&quot;&lt;liftoff&gt;&quot;)\n</code></pre>\n" +
+		"<table>\n<thead>\n<tr>\n<th>Pad</th>\n<th>Rocket</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>39A</td>\n<td>Falcon</td>\n</tr>\n</tbody>\n</table>\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCopyHTMLWhileLoading(t *testing.T) {
+	m := initialModel(options{repo: defaultRepoConfig(), metadataOnly: true}, settings{FirstRunDone: true})
+	m.selectedPost = &PostMetadata{PostTitle: "Launch Day"}
+	m.loadingBody = true
+	cmd := m.copyHTMLCmd()
+	if m.statusMessage != "Post is still loading" {
+		t.Errorf("status %q, want the post reported as loading", m.statusMessage)
+	}
+	if cmd == nil {
+		t.Error("got no command to clear the status")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// --- Shared HTTP client construction ---
+
+// tlsVersions maps --min-tls values to their crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion validates a --min-tls value such as "1.2".
+func parseTLSVersion(s string) (uint16, error) {
+	v, ok := tlsVersions[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version %q (want 1.0, 1.1, 1.2 or 1.3)", s)
+	}
+	return v, nil
+}
+
+// newHTTPClient builds the client used for every outgoing request. Connections
+// negotiating a TLS version below minTLS are refused.
+func newHTTPClient(timeout time.Duration, minTLS uint16) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: minTLS}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// fetchClient is what posts are fetched with: the HTTP client, how many
+// times doWithRetry retries a request after a network error, a 5xx or a 429,
+// and who to tell of each retry, if anyone.
+type fetchClient struct {
+	client   *http.Client
+	retries  int
+	retrying retryFunc
+}
+
+// defaultRetries is how many times a fetch is retried unless --retries says.
+const defaultRetries = 3
+
+// newFetchClient builds the client posts are fetched with, retrying as opts say.
+func newFetchClient(opts options) fetchClient {
+	return fetchClient{client: newHTTPClient(20*time.Second, opts.minTLS), retries: opts.retries}
+}
+
+// retryBackoff is the wait before the first retry, doubled before each after it.
+const retryBackoff = 200 * time.Millisecond
+
+// doWithRetry sends req through c, retrying failures that may pass: network
+// errors, server errors and throttling, and telling c.retrying of each retry.
+// Other 4xx responses come back at once, as does an exhausted rate limit,
+// which won't reset in time. req must be safe to send again, as a GET is.
+func doWithRetry(c fetchClient, req *http.Request) (*http.Response, error) {
+	wait := retryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.client.Do(req)
+		if attempt == c.retries || !isTransient(resp, err) {
+			return resp, err
+		}
+		reason := fmt.Sprint(err)
+		if resp != nil {
+			reason = resp.Status
+			io.Copy(io.Discard, resp.Body) // So the connection can be reused
+			resp.Body.Close()
+		}
+		log.Printf("Retrying %s in %s after %s", req.URL, wait, reason)
+		c.retrying.report(attempt+2, c.retries+1)
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		wait *= 2
+	}
+}
+
+// isTransient reports whether a request that got resp and err is worth retrying.
+func isTransient(resp *http.Response, err error) bool {
+	switch {
+	case err != nil:
+		return true
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return rateLimitError(resp) == nil
+	default:
+		return resp.StatusCode >= 500
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClient returns a fetch client that retries a failure retries times.
+func testClient(retries int) fetchClient {
+	return fetchClient{client: newHTTPClient(5*time.Second, 0), retries: retries}
+}
+
+// get sends a GET for url through doWithRetry with client, returning the body
+// of a response.
+func get(t *testing.T, client fetchClient, url string) (*http.Response, string, error) {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := doWithRetry(client, req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body), nil
+}
+
+func TestDoWithRetry(t *testing.T) {
+	t.Run("fails twice then succeeds", func(t *testing.T) {
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			if attempts.Load() <= 2 {
+				http.Error(w, "try again", http.StatusBadGateway)
+				return
+			}
+			io.WriteString(w, "third time")
+		}))
+		defer srv.Close()
+
+		resp, body, err := get(t, testClient(3), srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || body != "third time" {
+			t.Errorf("got %s %q, want 200 %q", resp.Status, body, "third time")
+		}
+		if attempts.Load() != 3 {
+			t.Errorf("got %d attempts, want 3", attempts.Load())
+		}
+	})
+
+	t.Run("exhausted rate limit is not retried", func(t *testing.T) {
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer srv.Close()
+
+		resp, _, err := get(t, testClient(3), srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("got %s, want 429", resp.Status)
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("got %d attempts, want 1", attempts.Load())
+		}
+	})
+
+	t.Run("no retries on 5xx", func(t *testing.T) {
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		resp, _, err := get(t, testClient(0), srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("got %s, want 503", resp.Status)
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("got %d attempts, want 1", attempts.Load())
+		}
+	})
+
+	t.Run("no retries on network error", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		url := srv.URL
+		srv.Close() // Nothing listens there any more
+
+		start := time.Now()
+		if _, _, err := get(t, testClient(0), url); err == nil {
+			t.Fatal("got no error from a closed server")
+		}
+		if elapsed := time.Since(start); elapsed >= retryBackoff {
+			t.Errorf("took %s, as if it waited to retry", elapsed)
+		}
+	})
+}
+
+func TestRetryReports(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= 2 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	updates, progress, retrying := newFetchProgress()
+	var reports []string
+	client := testClient(3)
+	client.retrying = func(attempt, attempts int) {
+		reports = append(reports, fmt.Sprintf("%d/%d", attempt, attempts))
+		retrying(attempt, attempts)
+	}
+	progress(1, 5)
+	req, err := http.NewRequest("GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := doWithRetry(client, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if want := []string{"2/4", "3/4"}; !slices.Equal(reports, want) {
+		t.Errorf("got retries %q, want %q", reports, want)
+	}
+	// The report waiting for the model has the files done as well as the retry
+	if got := <-updates; got.loaded != 1 || got.total != 5 || got.attempt != 3 || got.attempts != 4 {
+		t.Errorf("got progress %+v", got)
+	}
+}
+
+func TestNewHTTPClientMinTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	for _, tt := range []struct {
+		minTLS uint16
+		ok     bool
+	}{
+		{tls.VersionTLS12, true},
+		{tls.VersionTLS13, false},
+	} {
+		client := newHTTPClient(5*time.Second, tt.minTLS)
+		transport := client.Transport.(*http.Transport)
+		if transport.TLSClientConfig.MinVersion != tt.minTLS {
+			t.Errorf("MinVersion is %x, want %x", transport.TLSClientConfig.MinVersion, tt.minTLS)
+		}
+		transport.TLSClientConfig.RootCAs = roots
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if (err == nil) != tt.ok {
+			t.Errorf("min TLS %x against a TLS 1.2 server: got error %v, want success %v", tt.minTLS, err, tt.ok)
+		}
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	for s, want := range map[string]uint16{"1.0": tls.VersionTLS10, "1.1": tls.VersionTLS11, "1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13} {
+		if got, err := parseTLSVersion(s); err != nil || got != want {
+			t.Errorf("parseTLSVersion(%q) = %x, %v; want %x", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "1", "1.4", "TLS1.2"} {
+		if _, err := parseTLSVersion(s); err == nil {
+			t.Errorf("parseTLSVersion(%q) gave no error", s)
+		}
+	}
+}
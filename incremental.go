@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Incremental fetching ---
+
+// maxCompareFiles is the most files GitHub's compare API lists. A comparison
+// that long may have left some out, so everything is fetched instead.
+const maxCompareFiles = 300
+
+// compareFile is a file changed between two commits, as the compare API lists it.
+type compareFile struct {
+	Filename         string `json:"filename"`
+	Status           string `json:"status"` // "added", "modified", "removed", "renamed", ...
+	PreviousFilename string `json:"previous_filename"`
+	RawURL           string `json:"raw_url"`
+}
+
+type compareResult struct {
+	Status string        `json:"status"` // "ahead" when base is an ancestor of head
+	Files  []compareFile `json:"files"`
+}
+
+// incrementalFetchCmd loads the repo's posts by updating the cache at
+// cachePath with only the files changed since the commit it was saved at.
+// Without a cached commit, with refresh, or when the comparison can't be
+// trusted, every post is fetched, and the commit noted for next time.
+func incrementalFetchCmd(repo repoConfig, client fetchClient, cachePath string, refresh bool, progress progressFunc) tea.Cmd {
+	return func() tea.Msg {
+		head, err := fetchHeadCommit(client, repo)
+		if err != nil {
+			log.Println(err)
+			return postsLoadedMsg{err: err}
+		}
+
+		cache, err := loadCache(cachePath)
+		if err == nil && cache.Commit != "" && !refresh {
+			if cache.Commit == head {
+				return postsLoadedMsg{posts: cache.Posts, commit: head}
+			}
+			compare, err := fetchCompare(client, repo, cache.Commit, head)
+			if err == nil && compare.Status == "ahead" && len(compare.Files) < maxCompareFiles {
+				return updatePosts(client, repo, cache, compare.Files, head, progress)
+			}
+			if err == nil {
+				err = fmt.Errorf("comparison is %q with %d files", compare.Status, len(compare.Files))
+			}
+			log.Printf("Fetching every post, %s...%s can't be applied: %v", cache.Commit, head, err)
+		}
+
+		posts, err := fetchRepoPosts(client, repo, progress)
+		if len(posts) == 0 && err != nil {
+			return postsLoadedMsg{err: err}
+		}
+		if err != nil {
+			// Some files failed; without a commit the next fetch is a full one again
+			head = ""
+		}
+		return postsLoadedMsg{posts: posts, commit: head}
+	}
+}
+
+// fetchHeadCommit returns the SHA of the repo's default branch.
+func fetchHeadCommit(client fetchClient, repo repoConfig) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/commits/HEAD", repo.apiBase, repo.owner, repo.name)
+	body, err := httpGet(client, url)
+	if err != nil {
+		return "", err
+	}
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.Unmarshal(body, &commit); err != nil {
+		return "", fmt.Errorf("unmarshalling API JSON from %s: %w", url, err)
+	}
+	if commit.SHA == "" {
+		return "", fmt.Errorf("no commit SHA in %s", url)
+	}
+	return commit.SHA, nil
+}
+
+// fetchCompare lists the files changed from base to head.
+func fetchCompare(client fetchClient, repo repoConfig, base, head string) (compareResult, error) {
+	var compare compareResult
+	url := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", repo.apiBase, repo.owner, repo.name, base, head)
+	body, err := httpGet(client, url)
+	if err != nil {
+		return compare, err
+	}
+	if err := json.Unmarshal(body, &compare); err != nil {
+		return compare, fmt.Errorf("unmarshalling API JSON from %s: %w", url, err)
+	}
+	return compare, nil
+}
+
+// updatePosts applies a comparison to the cached posts: posts whose files
+// were removed or renamed away are dropped, and added or modified files are
+// downloaded afresh. A post whose download fails keeps its cached version,
+// and the cached commit is kept so the next fetch tries the changes again.
+func updatePosts(client fetchClient, repo repoConfig, cache postCache, files []compareFile, head string, progress progressFunc) tea.Msg {
+	gone := make(map[string]bool)
+	var changed []GitHubContent
+	for _, f := range files {
+		if f.PreviousFilename != "" && isPostFile(repo, f.PreviousFilename) {
+			gone[f.PreviousFilename] = true
+		}
+		if !isPostFile(repo, f.Filename) {
+			continue
+		}
+		if f.Status == "removed" {
+			gone[f.Filename] = true
+			continue
+		}
+		changed = append(changed, GitHubContent{Name: path.Base(f.Filename), Path: f.Filename, Type: "file", DownloadURL: f.RawURL})
+	}
+	if len(gone) == 0 && len(changed) == 0 {
+		return postsLoadedMsg{posts: cache.Posts, commit: head}
+	}
+
+	fetched, err := fetchPostFiles(client, changed, progress)
+	log.Printf("Updated %d and removed %d posts for %s..%s", len(fetched), len(gone), cache.Commit, head)
+	if err != nil {
+		head = cache.Commit
+	}
+	for _, p := range fetched {
+		gone[p.SourcePath] = true
+	}
+	posts := fetched
+	for _, p := range cache.Posts {
+		if !gone[p.SourcePath] {
+			posts = append(posts, p)
+		}
+	}
+	posts = dedupePosts(posts)
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].PublishDate.After(posts[j].PublishDate)
+	})
+	return postsLoadedMsg{posts: posts, commit: head}
+}
+
+// isPostFile reports whether a repo path is a post the directory listing
+// would include: an .mdx file directly in the posts directory.
+func isPostFile(repo repoConfig, file string) bool {
+	dir := strings.Trim(repo.path, "/")
+	if dir == "" {
+		dir = "."
+	}
+	return path.Dir(file) == dir && strings.HasSuffix(file, ".mdx")
+}
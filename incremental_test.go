@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// compareFixture stands in for the GitHub API and raw file downloads of a
+// repo whose posts are in posts/.
+type compareFixture struct {
+	head    string
+	compare compareResult
+	listing []GitHubContent
+	files   map[string]string // Raw file contents by name; missing ones fail
+}
+
+func (f *compareFixture) serve(t *testing.T) (*httptest.Server, repoConfig) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/commits/HEAD", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"sha": %q}`, f.head)
+	})
+	mux.HandleFunc("/repos/o/r/compare/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(f.compare)
+	})
+	mux.HandleFunc("/repos/o/r/contents/posts", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(f.listing)
+	})
+	mux.HandleFunc("/raw/", func(w http.ResponseWriter, r *http.Request) {
+		content, ok := f.files[strings.TrimPrefix(r.URL.Path, "/raw/")]
+		if !ok {
+			http.Error(w, "broken", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, content)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, repoConfig{owner: "o", name: "r", path: "posts", apiBase: srv.URL}
+}
+
+// fixturePost is a post file titled title, published day days into 2024.
+func fixturePost(title string, day int) string {
+	return fmt.Sprintf("---\ntitle: %s\npublishDate: %s\n---\nBody\n", title, time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC).Format(time.DateOnly))
+}
+
+// cachedPost is a post as the cache holds it, from posts/<slug>.mdx.
+func cachedPost(slug string, day int) PostMetadata {
+	return PostMetadata{PostTitle: strings.ToUpper(slug), Slug: slug, SourcePath: "posts/" + slug + ".mdx", PublishDate: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func titles(posts []PostMetadata) []string {
+	var names []string
+	for _, p := range posts {
+		names = append(names, p.PostTitle)
+	}
+	return names
+}
+
+func TestIncrementalFetch(t *testing.T) {
+	cache := postCache{Commit: "base", Posts: []PostMetadata{
+		cachedPost("d", 4), cachedPost("c", 3), cachedPost("b", 2), cachedPost("a", 1),
+	}}
+	raw := func(srvURL, name string) string { return srvURL + "/raw/" + name }
+
+	tests := []struct {
+		name       string
+		fixture    compareFixture
+		changes    func(srvURL string) []compareFile
+		wantTitles []string
+		wantCommit string
+	}{
+		{
+			name: "added, modified, removed and renamed",
+			fixture: compareFixture{head: "head", files: map[string]string{
+				"a.mdx": fixturePost("A v2", 1), "c2.mdx": fixturePost("C renamed", 3), "e.mdx": fixturePost("E", 5),
+			}},
+			changes: func(srvURL string) []compareFile {
+				return []compareFile{
+					{Filename: "posts/a.mdx", Status: "modified", RawURL: raw(srvURL, "a.mdx")},
+					{Filename: "posts/b.mdx", Status: "removed"},
+					{Filename: "posts/c2.mdx", Status: "renamed", PreviousFilename: "posts/c.mdx", RawURL: raw(srvURL, "c2.mdx")},
+					{Filename: "posts/e.mdx", Status: "added", RawURL: raw(srvURL, "e.mdx")},
+					{Filename: "README.md", Status: "modified", RawURL: raw(srvURL, "README.md")},
+				}
+			},
+			wantTitles: []string{"E", "D", "C renamed", "A v2"},
+			wantCommit: "head",
+		},
+		{
+			name: "too many files for the comparison",
+			fixture: compareFixture{head: "head", files: map[string]string{
+				"f.mdx": fixturePost("F", 6),
+			}},
+			changes: func(srvURL string) []compareFile {
+				files := make([]compareFile, maxCompareFiles)
+				for i := range files {
+					files[i] = compareFile{Filename: fmt.Sprintf("posts/%d.mdx", i), Status: "added"}
+				}
+				return files
+			},
+			wantTitles: []string{"F"},
+			wantCommit: "head",
+		},
+		{
+			name: "failed download",
+			fixture: compareFixture{head: "head", files: map[string]string{
+				"a.mdx": fixturePost("A v2", 1),
+			}},
+			changes: func(srvURL string) []compareFile {
+				return []compareFile{
+					{Filename: "posts/a.mdx", Status: "modified", RawURL: raw(srvURL, "a.mdx")},
+					{Filename: "posts/e.mdx", Status: "added", RawURL: raw(srvURL, "e.mdx")},
+				}
+			},
+			wantTitles: []string{"D", "C", "B", "A v2"},
+			wantCommit: "base",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.fixture
+			srv, repo := f.serve(t)
+			f.compare = compareResult{Status: "ahead", Files: tt.changes(srv.URL)}
+			f.listing = []GitHubContent{{Name: "f.mdx", Path: "posts/f.mdx", Type: "file", DownloadURL: raw(srv.URL, "f.mdx")}}
+			path := filepath.Join(t.TempDir(), "posts.json")
+			if err := saveCache(path, cache); err != nil {
+				t.Fatal(err)
+			}
+
+			msg := incrementalFetchCmd(repo, testClient(0), path, false, nil)().(postsLoadedMsg)
+			if msg.err != nil {
+				t.Fatal(msg.err)
+			}
+			if got := titles(msg.posts); !slices.Equal(got, tt.wantTitles) {
+				t.Errorf("got posts %q, want %q", got, tt.wantTitles)
+			}
+			if msg.commit != tt.wantCommit {
+				t.Errorf("got commit %q, want %q", msg.commit, tt.wantCommit)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Keymap ---
+
+// binding is a key binding tagged with the screens it applies to, so the help
+// overlay only lists keys that work where the user is.
+type binding struct {
+	key.Binding
+	screens []screenState
+}
+
+func newBinding(screens []screenState, keys []string, helpKey, desc string) binding {
+	return binding{
+		Binding: key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKey, desc)),
+		screens: screens,
+	}
+}
+
+// appliesTo reports whether the binding is active on screen s.
+func (b binding) appliesTo(s screenState) bool {
+	for _, screen := range b.screens {
+		if screen == s {
+			return true
+		}
+	}
+	return false
+}
+
+type keyMap struct {
+	Continue    binding
+	Quit        binding
+	Exit        binding
+	Back        binding
+	Open        binding
+	Close       binding
+	Up          binding
+	Down        binding
+	PageUp      binding
+	PageDown    binding
+	Top         binding
+	Bottom      binding
+	Left        binding
+	Right       binding
+	Raw         binding
+	Wrap        binding
+	Info        binding
+	LineNumbers binding
+	Expand      binding
+	CopyLinks   binding
+	Footnotes   binding
+	CopyHTML    binding
+	Reveal      binding
+	Source      binding
+	Web         binding
+	Follow      binding
+	Theme       binding
+	ReadingSize binding
+	RelatedNext binding
+	RelatedPrev binding
+	OpenRelated binding
+	PostBack    binding
+	PostForward binding
+	Sort        binding
+	JumpDate    binding
+	Newest      binding
+	Oldest      binding
+	Archive     binding
+	ArchiveOpen binding
+	ArchiveFold binding
+	ArchiveBack binding
+	Today       binding
+	TodayBack   binding
+	Random      binding
+	Stats       binding
+	StatsBack   binding
+	Tags        binding
+	TagPick     binding
+	TagsBack    binding
+	Numbers     binding
+	StatusBar   binding
+	Report      binding
+	Help        binding
+}
+
+func newKeyMap() keyMap {
+	splash := []screenState{splashScreen}
+	posts := []screenState{listScreen}
+	post := []screenState{postDetailScreen}
+	firstRun := []screenState{firstRunScreen}
+	archive := []screenState{archiveScreen}
+	today := []screenState{todayScreen}
+	stats := []screenState{statsScreen}
+	tags := []screenState{tagsScreen}
+	browse := []screenState{listScreen, postDetailScreen, archiveScreen, todayScreen}
+	appearance := []screenState{postDetailScreen, firstRunScreen}
+	everywhere := []screenState{splashScreen, listScreen, postDetailScreen, firstRunScreen, archiveScreen, todayScreen, statsScreen, tagsScreen}
+
+	return keyMap{
+		Continue:    newBinding(append(splash, firstRun...), []string{"enter"}, "enter", "continue"),
+		Quit:        newBinding(append(splash, firstRun...), []string{"q", "esc"}, "q/esc", "quit"),
+		Exit:        newBinding(everywhere, []string{"Q", "ctrl+c"}, "Q", "quit from any screen"),
+		Back:        newBinding(posts, []string{"q", "esc", "b", "backspace"}, "q/b", "back to the splash screen"),
+		Open:        newBinding(append(posts, today...), []string{"enter"}, "enter", "open the highlighted post"),
+		Close:       newBinding(post, []string{"q", "esc", "b", "backspace"}, "q/b", "back to the post list"),
+		Up:          newBinding(browse, []string{"up", "k"}, "↑/k", "scroll up"),
+		Down:        newBinding(browse, []string{"down", "j"}, "↓/j", "scroll down"),
+		PageUp:      newBinding(post, []string{"pgup"}, "pgup", "page up"),
+		PageDown:    newBinding(post, []string{"pgdown"}, "pgdn", "page down"),
+		Top:         newBinding(post, []string{"home"}, "home", "go to top"),
+		Bottom:      newBinding(post, []string{"end"}, "end", "go to bottom"),
+		Left:        newBinding(post, []string{"left"}, "←", "scroll left (wrapping off)"),
+		Right:       newBinding(post, []string{"right"}, "→", "scroll right (wrapping off)"),
+		Raw:         newBinding(post, []string{"m"}, "m", "toggle raw markdown"),
+		Wrap:        newBinding(post, []string{"w"}, "w", "toggle wrapping and sideways scrolling"),
+		Info:        newBinding(post, []string{"i"}, "i", "toggle metadata block"),
+		LineNumbers: newBinding(post, []string{"L"}, "L", "toggle line numbers"),
+		Expand:      newBinding(post, []string{"e"}, "e", "expand collapsed code block"),
+		CopyLinks:   newBinding(post, []string{"U"}, "U", "copy footnote URLs"),
+		Footnotes:   newBinding(post, []string{"f"}, "f", "show or hide footnotes"),
+		CopyHTML:    newBinding(post, []string{"H"}, "H", "copy post as HTML"),
+		Reveal:      newBinding(post, []string{"v"}, "v", "reveal spoiler"),
+		Source:      newBinding(post, []string{"G"}, "G", "open source on GitHub"),
+		Web:         newBinding(append(posts, post...), []string{"o"}, "o", "open the post on the web"),
+		Follow:      newBinding(post, []string{"F"}, "F", "follow the bottom on updates"),
+		Theme:       newBinding(appearance, []string{"T"}, "T", "cycle theme"),
+		ReadingSize: newBinding(appearance, []string{"z"}, "z", "cycle reading size"),
+		RelatedNext: newBinding(post, []string{"tab"}, "tab", "highlight next related post"),
+		RelatedPrev: newBinding(post, []string{"shift+tab"}, "shift+tab", "highlight previous related post"),
+		OpenRelated: newBinding(post, []string{"enter"}, "enter", "open highlighted related post"),
+		PostBack:    newBinding(post, []string{"["}, "[", "back to the previous post"),
+		PostForward: newBinding(post, []string{"]"}, "]", "forward to the next post"),
+		Sort:        newBinding(posts, []string{"s"}, "s", "cycle sort order"),
+		JumpDate:    newBinding(posts, []string{"d"}, "d", "jump to a date"),
+		Newest:      newBinding(posts, []string{"N"}, "N", "jump to the newest post"),
+		Oldest:      newBinding(posts, []string{"O"}, "O", "jump to the oldest post"),
+		Archive:     newBinding(posts, []string{"A"}, "A", "browse the archive by month"),
+		ArchiveOpen: newBinding(archive, []string{"enter", "right", "l"}, "enter/→", "toggle a year or month, open a post"),
+		ArchiveFold: newBinding(archive, []string{"left", "h"}, "←/h", "fold the year or month"),
+		ArchiveBack: newBinding(archive, []string{"q", "esc", "b", "backspace", "A"}, "q/b/A", "back to the post list"),
+		Today:       newBinding(append(splash, posts...), []string{"Y"}, "Y", "posts from this day in earlier years"),
+		TodayBack:   newBinding(today, []string{"q", "esc", "b", "backspace", "Y"}, "q/b/Y", "back to the post list"),
+		Random:      newBinding(append(splash, posts...), []string{"r"}, "r", "open a random post"),
+		Stats:       newBinding(posts, []string{"S"}, "S", "your reading stats"),
+		StatsBack:   newBinding(stats, []string{"q", "esc", "b", "backspace", "S"}, "q/b/S", "back to the post list"),
+		Tags:        newBinding(posts, []string{"t"}, "t", "browse by tag"),
+		TagPick:     newBinding(tags, []string{"enter"}, "enter", "show the posts with this tag"),
+		TagsBack:    newBinding(tags, []string{"q", "esc", "b", "backspace", "t"}, "q/b/t", "back to all posts"),
+		Numbers:     newBinding(posts, []string{"#"}, "#", "toggle list numbers"),
+		StatusBar:   newBinding(posts, []string{"B"}, "B", "toggle list status bar"),
+		Report:      newBinding(post, []string{"!"}, "!", "report this post"),
+		Help:        newBinding(everywhere, []string{"?"}, "?", "toggle help"),
+	}
+}
+
+// all returns every binding in the order help lists them.
+func (k keyMap) all() []binding {
+	return []binding{
+		k.Continue, k.Open, k.Up, k.Down, k.ArchiveOpen, k.ArchiveFold,
+		k.PageUp, k.PageDown, k.Top, k.Bottom, k.Left, k.Right,
+		k.RelatedNext, k.RelatedPrev, k.OpenRelated, k.PostBack, k.PostForward,
+		k.Raw, k.Wrap, k.Info, k.LineNumbers, k.Expand, k.Reveal, k.Theme, k.ReadingSize,
+		k.Follow, k.Footnotes, k.CopyLinks, k.CopyHTML, k.Source, k.Web,
+		k.Sort, k.JumpDate, k.Newest, k.Oldest, k.Archive, k.Today, k.Random, k.Stats, k.Tags, k.TagPick, k.Numbers, k.StatusBar,
+		k.Report, k.Back, k.Close, k.ArchiveBack, k.TodayBack, k.StatsBack, k.TagsBack, k.Help, k.Quit, k.Exit,
+	}
+}
+
+// forScreen returns the enabled bindings that apply on screen s.
+func (k keyMap) forScreen(s screenState) []key.Binding {
+	var bindings []key.Binding
+	for _, b := range k.all() {
+		if b.Enabled() && b.appliesTo(s) {
+			bindings = append(bindings, b.Binding)
+		}
+	}
+	return bindings
+}
+
+// helpView renders the keys available on screen s as a boxed two-column
+// table, boxed in ASCII when asciiOnly.
+func helpView(k keyMap, s screenState, asciiOnly bool) string {
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	descStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"})
+
+	bindings := k.forScreen(s)
+	keyWidth := 0
+	for _, b := range bindings {
+		keyWidth = max(keyWidth, lipgloss.Width(b.Help().Key))
+	}
+
+	lines := []string{fmt.Sprintf("Keys: %s", s), ""}
+	for _, b := range bindings {
+		lines = append(lines, keyStyle.Width(keyWidth+2).Render(b.Help().Key)+descStyle.Render(b.Help().Desc))
+	}
+	return lipgloss.NewStyle().
+		Border(uiBorder(lipgloss.RoundedBorder(), asciiOnly)).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Metadata-only mode: post bodies fetched on open ---
+
+type postBodyMsg struct {
+	key     string // Post the body belongs to, so a late reply for another post is dropped
+	content string
+	err     error
+}
+
+// metadataOnly wraps a fetch so posts keep their metadata but not their
+// Content, which is fetched again from SourceURL when a post is opened.
+// Anything small derived from the body, like ReadingMinutes, is computed
+// before the body is dropped; PlainText, as big as the body, goes with it, so
+// only metadata is searched. Posts from the offline cache keep theirs, as
+// fetching it again is what just failed.
+func metadataOnly(fetch tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := fetch().(postsLoadedMsg)
+		if !ok || !msg.cachedAt.IsZero() {
+			return msg
+		}
+		for i := range msg.posts {
+			if msg.posts[i].SourceURL != "" {
+				msg.posts[i].Content, msg.posts[i].PlainText = "", ""
+			}
+		}
+		return msg
+	}
+}
+
+// fetchBodyCmd downloads the body of a post loaded in metadata-only mode.
+func fetchBodyCmd(p PostMetadata, client fetchClient) tea.Cmd {
+	return func() tea.Msg {
+		body, err := httpGet(client, p.SourceURL)
+		if err != nil {
+			return postBodyMsg{key: p.key(), err: err}
+		}
+		content := string(body)
+		if _, rest, ok := splitFrontmatter(content); ok {
+			content = rest
+		}
+		return postBodyMsg{key: p.key(), content: strings.TrimSpace(content)}
+	}
+}
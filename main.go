@@ -2,27 +2,42 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"regexp" // Added regexp import
+	"slices"
 	"sort"
 	"strconv" // For footnote check
 	"strings"
+	"sync"
+	"syscall"
+	"text/template"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport" // Added viewport import
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour" // Added glamour import
+	"github.com/charmbracelet/glamour/styles"
 	"github.com/charmbracelet/lipgloss"
 	ssh "github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/bubbletea"
-	"gopkg.in/yaml.v3"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/term"
 )
 
 // --- Enums for screen state ---
@@ -31,39 +46,227 @@ type screenState int
 const (
 	splashScreen screenState = iota
 	listScreen
+	firstRunScreen
+	archiveScreen
+	postDetailScreen
+	todayScreen
+	statsScreen
+	tagsScreen
 )
 
+func (s screenState) String() string {
+	switch s {
+	case splashScreen:
+		return "splash"
+	case listScreen:
+		return "posts"
+	case firstRunScreen:
+		return "first run"
+	case archiveScreen:
+		return "archive"
+	case postDetailScreen:
+		return "post"
+	case todayScreen:
+		return "on this day"
+	case statsScreen:
+		return "stats"
+	case tagsScreen:
+		return "tags"
+	default:
+		return "unknown"
+	}
+}
+
+// --- Enums for list sort order ---
+type sortMode int
+
+const (
+	sortNewest sortMode = iota
+	sortOldest
+	sortShortest
+	sortLongest
+	numSortModes
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortOldest:
+		return "oldest first"
+	case sortShortest:
+		return "quick reads first"
+	case sortLongest:
+		return "long reads first"
+	default:
+		return "newest first"
+	}
+}
+
+// sortPosts orders posts in place for the given mode. Ties fall back to newest first.
+func sortPosts(posts []PostMetadata, mode sortMode) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		a, b := posts[i], posts[j]
+		switch mode {
+		case sortOldest:
+			// Undated posts stay at the bottom, as they are when newest come first
+			if a.PublishDate.IsZero() != b.PublishDate.IsZero() {
+				return b.PublishDate.IsZero()
+			}
+			return a.PublishDate.Before(b.PublishDate)
+		case sortShortest:
+			if a.ReadingMinutes != b.ReadingMinutes {
+				return a.ReadingMinutes < b.ReadingMinutes
+			}
+		case sortLongest:
+			if a.ReadingMinutes != b.ReadingMinutes {
+				return a.ReadingMinutes > b.ReadingMinutes
+			}
+		}
+		return a.PublishDate.After(b.PublishDate)
+	})
+}
+
+// --- Command-line options ---
+type options struct {
+	noAltScreen   bool          // Render inline instead of switching to the terminal's alternate screen
+	reportWebhook string        // When set, readers can report posts to this URL
+	sshMode       bool          // Running as a session of the SSH server
+	highlight     string        // Selected list item color, overrides the adaptive default
+	highlightBg   string        // Selected list item background, none by default
+	gistID        string        // Load posts from this Gist instead of the blog repo
+	reduceMotion  bool          // Avoid decorative animation
+	asciiOnly     bool          // Draw only ASCII, for terminals and fonts that garble anything else
+	minTLS        uint16        // Lowest TLS version accepted for outgoing requests
+	retries       int           // Times a failed fetch is retried
+	homeSlug      string        // Post shown first on entering the list, instead of the latest
+	homePage      *PostMetadata // Front page parsed from --home-file, takes precedence over homeSlug
+	metadataOnly  bool          // Keep only post metadata in memory, fetching a body when its post is opened
+	goodbye       string        // Printed once the UI exits, nothing when empty
+	numberedList  bool          // Start with list items numbered
+	noFirstRun    bool          // Skip the one-time appearance prompt
+	math          bool          // Convert $...$ and $$...$$ LaTeX to Unicode
+	noStatusBar   bool          // Start with the list's status bar hidden
+	collapseCode  int           // Collapse fenced code blocks longer than this many lines, 0 to disable
+	scrollLines   int           // Lines moved per up/down keypress in a post
+	motd          string        // Message of the day shown above the splash over SSH, none when empty
+	splashAnim    splashAnim    // Decoration animated under the splash message
+	links         linkMode      // Links as footnotes, left inline, or as plain text
+	typewriter    int           // Characters per second the splash is typed out at, 0 to show it at once
+	showPrivate   bool          // List posts marked private: true
+	refresh       bool          // Never fall back to the offline cache when fetching fails
+	repo          repoConfig    // GitHub repository directory the posts are read from
+	style         string        // Glamour style name or JSON style file to start with, the saved style when empty
+	incremental   bool          // Update the cached posts with the files changed since its commit
+	randomUnread  bool          // Favor unread posts when opening one at random
+	autoTags      bool          // Show tags suggested for posts that declare none
+	description   string        // text/template for the line under each list item, the default when empty
+	freshness     freshnessAges // Ages at which posts stop being badged fresh and recent, the defaults when zero
+	formatDate    dateFormat    // Writes dates for the reader's locale, ISO 8601 when nil
+}
+
 // --- Structs for Post Data ---
 type PostMetadata struct {
-	PostTitle   string    `yaml:"title"`
-	Excerpt     string    `yaml:"excerpt"`
-	PublishDate time.Time `yaml:"publishDate"`
-	Category    string    `yaml:"category"`
-	Tags        []string  `yaml:"tags"`
-	Slug        string    `yaml:"slug"`
-	Image       string    `yaml:"image"`
+	PostTitle   string    `yaml:"title" toml:"title" json:"title"`
+	Excerpt     string    `yaml:"excerpt" toml:"excerpt" json:"excerpt"`
+	PublishDate time.Time `yaml:"publishDate" toml:"publishDate" json:"publishDate"`
+	UpdateDate  time.Time `yaml:"updateDate" toml:"updateDate" json:"updateDate"` // Last substantial edit, zero if never updated
+	Category    string    `yaml:"category" toml:"category" json:"category"`
+	Author      string    `yaml:"author" toml:"author" json:"author"`
+	Tags        []string  `yaml:"tags" toml:"tags" json:"tags"`
+	Slug        string    `yaml:"slug" toml:"slug" json:"slug"`
+	Aliases     []string  `yaml:"aliases" toml:"aliases" json:"aliases"` // Old slugs that still lead to this post
+	Image       string    `yaml:"image" toml:"image" json:"image"`
+	AccentColor string    `yaml:"accentColor" toml:"accentColor" json:"accentColor"` // Optional per-post accent, hex (#RGB/#RRGGBB) or ANSI 0-255
+	Dir         string    `yaml:"dir" toml:"dir" json:"dir"`                         // Text direction, "rtl" or "ltr"; detected from the text when unset
+	Private     bool      `yaml:"private" toml:"private" json:"private"`             // Kept out of public outputs, and hidden unless --show-private
 	Content     string    // Added to store the full post content
+
+	ReadingMinutes int      `yaml:"-" toml:"-"` // Estimated reading time, computed from Content at parse time
+	SourceURL      string   `yaml:"-" toml:"-"` // Where the post was downloaded from, to fetch Content again in metadata-only mode
+	SourcePath     string   `yaml:"-" toml:"-"` // Path of the post's file in its repo or Gist, empty for the --home-file page
+	ListTitle      string   `yaml:"-" toml:"-"` // PostTitle with its date appended when another post has the same title
+	SuggestedTags  []string `yaml:"-" toml:"-"` // Guessed from Content at parse time for posts without tags, shown with --auto-tags
+	PlainText      string   `yaml:"-" toml:"-"` // Content reduced by plainText at parse time, for searching and snippets
 }
 
 // Implement list.Item for PostMetadata
-func (p PostMetadata) Title() string { return p.PostTitle } // Updated to use PostTitle
-func (p PostMetadata) Description() string {
-	desc := p.PublishDate.Format("2006-01-02")
-	if p.Category != "" {
-		desc += " | Cat: " + p.Category
+func (p PostMetadata) Title() string {
+	if p.ListTitle != "" {
+		return p.ListTitle
 	}
-	if len(p.Tags) > 0 {
-		desc += " | Tags: " + strings.Join(p.Tags, ", ")
+	return p.PostTitle
+}
+
+// FilterValue is the post's metadata and, after a newline, its text, which
+// searchFilter matches separately.
+func (p PostMetadata) FilterValue() string {
+	return p.PostTitle + " " + p.Category + " " + strings.Join(p.Tags, " ") + "\n" + p.PlainText
+}
+
+// accent returns the color used to highlight this post, honoring its frontmatter override.
+func (p PostMetadata) accent() lipgloss.Color {
+	if p.AccentColor != "" {
+		return lipgloss.Color(p.AccentColor)
+	}
+	return lipgloss.Color("205")
+}
+
+// wordsPerMinute is the reading speed used for reading time estimates.
+const wordsPerMinute = 200
+
+// readingMinutes estimates how long content takes to read, rounding up to at least a minute.
+func readingMinutes(content string) int {
+	words := len(strings.Fields(content))
+	if words == 0 {
+		return 0
+	}
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
+
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// isValidColor reports whether s is a color lipgloss understands: a hex code or an ANSI 256 index.
+func isValidColor(s string) bool {
+	if hexColorRe.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// key identifies the post in caches, lookups and reading stats. Posts without
+// a slug go by a hash of their title, which can't be mistaken for another
+// post's slug.
+func (p PostMetadata) key() string {
+	if p.Slug != "" {
+		return p.Slug
 	}
-	return desc
+	sum := sha256.Sum256([]byte(p.PostTitle))
+	return "#" + hex.EncodeToString(sum[:8])
 }
-func (p PostMetadata) FilterValue() string { return p.PostTitle + " " + p.Category + " " + strings.Join(p.Tags, " ") } // Updated to use PostTitle
 
 // --- Messages ---
 type tickMsg time.Time
+type clearStatusMsg struct{ id int }
+type resizeRenderMsg struct{ seq int }
+
+// resizeDebounce is how long sizes must stay unchanged before the post is re-wrapped.
+const resizeDebounce = 100 * time.Millisecond
+
+// sizeTimeoutMsg arrives once sizeTimeout has passed after startup; if no
+// WindowSizeMsg came by then the UI lays itself out at the fallback size.
+type sizeTimeoutMsg struct{}
+
+const (
+	sizeTimeout    = 2 * time.Second
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
 type postsLoadedMsg struct {
-	posts []PostMetadata
-	err   error
+	posts    []PostMetadata
+	err      error
+	cachedAt time.Time // When the posts were fetched, if they came from the offline cache
+	commit   string    // Repo commit the posts match, with --incremental
 }
 
 // type gotPostsErrorMsg struct{ err error } // Not used in this simplified version
@@ -77,14 +280,73 @@ type model struct {
 	width            int
 	height           int
 	postList         list.Model
+	tagList          list.Model     // The tag menu
+	tagFilter        string         // Tag the post list is limited to, or ""
+	pendingFilter    tea.Cmd        // Filtering held back until typing pauses, see debounceFilter
+	filterSeq        int            // Numbers held back filtering so only the latest runs
+	posts            []PostMetadata // All loaded posts, independent of the list's order
+	relatedIdx       relatedIndex   // Tag/category index over posts
+	related          []int          // Posts related to the selected one, as indexes into posts
+	relatedCursor    int            // Highlighted related post, -1 for none
+	sortMode         sortMode
+	fetchProgress    fetchProgressMsg // Latest report of the fetch in flight
 	loadingPosts     bool
+	skeletonFrame    int // Shimmer position of the loading skeleton
+	splashFrame      int // Position of the splash animation
+	typed            int // Characters of the splash typed out so far
 	postsError       error
 	selectedPost     *PostMetadata
 	viewport         viewport.Model // Added viewport for post content
 	ready            bool           // For viewport initialization
+	showRaw          bool           // Show the raw markdown source instead of the glamour rendering
+	hideMetadata     bool           // Hide the metadata block above the rendered post
+	lineNumbers      bool           // Prefix each content line with its number
+	opts             options
+	statusMessage    string // Transient message shown in the footer
+	statusID         int    // Incremented per status so stale clear timers are ignored
+	reporting        bool   // Report reason prompt is open
+	reportInput      textinput.Model
+	reportsSent      int
+	lastReport       time.Time
+	history          postHistory  // Posts viewed before and after the selected one
+	archive          archiveView  // Year and month tree of the posts
+	today            todayView    // Posts from this day in earlier years
+	stats            readingStats // This reader's reading history
+	statsUser        string       // Who stats are saved for, none to keep them unsaved
+	rng              *rand.Rand   // Picks random posts
+	pendingRandom    bool         // Open a random post once the posts have loaded
+	jumping          bool         // Jump to date prompt is open
+	jumpInput        textinput.Model
+	prefs            settings // User preferences, persisted in local mode
+	keys             keyMap
+	showHelp         bool                    // Help overlay for the current screen is open
+	resizeSeq        int                     // Latest resize, so only the last debounce timer re-renders
+	renderedContent  string                  // What the viewport currently shows, one entry per row
+	collapsedBlocks  []int                   // Code blocks collapsed in the selected post, in document order
+	expandedBlocks   map[string]map[int]bool // Code blocks the reader expanded, per post
+	hiddenSpoilers   []int                   // Spoilers still hidden in the selected post, in document order
+	revealedSpoilers map[string]map[int]bool // Spoilers the reader revealed, per post
+	shownFootnotes   map[string]bool         // Posts whose footnotes section the reader expanded
+	footnoteURLs     []string                // Links of the selected post, in footnote order
+	clipboard        io.Writer               // SSH session to send OSC 52 copies to, nil for the local clipboard
+	loadingBody      bool                    // The selected post's body is being fetched (metadata-only mode)
+	follow           bool                    // Keep the viewport pinned to the bottom whenever its content changes
+	noWrap           bool                    // Render posts unwrapped and scroll sideways instead
+	renderCache      map[string]string       // Rendered post bodies keyed by post, style and width
+	stream           *renderStream           // A long post still rendering, if any
+	listDelegate     numberedDelegate        // Kept so toggling numbers can hand the list an updated copy
 }
 
-func initialModel() model {
+func initialModel(opts options, prefs settings) model {
+	if opts.style != "" {
+		prefs.GlamourStyle = opts.style
+	}
+	if opts.freshness == (freshnessAges{}) {
+		opts.freshness = defaultFreshness
+	}
+	if opts.formatDate == nil {
+		opts.formatDate = isoDate
+	}
 	// ... (existing list initialization) ...
 	delegate := list.NewDefaultDelegate()
 
@@ -102,36 +364,116 @@ func initialModel() model {
 		Background(adaptiveBg).
 		Padding(0, 0, 0, 2)
 
-	l := list.New([]list.Item{}, delegate, 0, 0)
+	// Selected item: a darker pink on light backgrounds and a brighter one on dark
+	// backgrounds keep the highlight readable in both appearances
+	var highlight lipgloss.TerminalColor = lipgloss.AdaptiveColor{Light: "#C2185B", Dark: "#FF79C6"}
+	if opts.highlight != "" {
+		highlight = lipgloss.Color(opts.highlight)
+	}
+	var highlightBg lipgloss.TerminalColor = adaptiveBg
+	if opts.highlightBg != "" {
+		highlightBg = lipgloss.Color(opts.highlightBg)
+	}
+
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Border(uiBorder(lipgloss.NormalBorder(), opts.asciiOnly), false, false, false, true).
+		BorderForeground(highlight).
+		Foreground(highlight).
+		Background(highlightBg).
+		Bold(true).
+		Padding(0, 0, 0, 1)
+
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedTitle.
+		Bold(false)
+
+	if opts.description == "" {
+		opts.description = defaultDescriptionTemplate
+	}
+	description := template.Must(parseDescriptionTemplate(opts.description)) // Checked at startup
+	numbered := numberedDelegate{DefaultDelegate: delegate, numbered: opts.numberedList, description: description, formatDate: opts.formatDate}
+	l := list.New([]list.Item{}, numbered, 0, 0)
 	l.Title = "Blog Posts"
-	l.SetShowStatusBar(true)
+	l.SetShowStatusBar(!opts.noStatusBar && !prefs.HideStatusBar)
 	l.SetFilteringEnabled(true)
+	l.Filter = searchFilter
+	l.KeyMap.Quit.SetHelp("q", "back") // q backs out to the splash, see keyMap.Back
 	l.Styles.Title = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
 	l.Styles.PaginationStyle = list.DefaultStyles().PaginationStyle.Foreground(lipgloss.Color("240"))
 	l.Styles.HelpStyle = list.DefaultStyles().HelpStyle.Foreground(lipgloss.Color("240"))
 
 	// Viewport setup - will be fully configured when a post is selected
-	vp := viewport.New(0,0) // Initial size, will be updated
+	vp := viewport.New(0, 0) // Initial size, will be updated
+
+	keys := newKeyMap()
+	keys.Report.SetEnabled(opts.reportWebhook != "")
+
+	ri := textinput.New()
+	ri.Placeholder = "optional"
+	ri.CharLimit = 280
+
+	ji := textinput.New()
+	ji.Placeholder = "YYYY, YYYY-MM or YYYY-MM-DD"
+	ji.CharLimit = 10
+
+	screen := splashScreen
+	if needsFirstRun(opts, prefs) {
+		screen = firstRunScreen
+	}
 
 	return model{
-		currentScreen:    splashScreen,
+		currentScreen:    screen,
 		splashMessage:    "Welcome to Space Coast Devs",
 		flashMessage:     "<Press Enter to Continue>",
 		showFlashMessage: true,
 		loadingPosts:     false,
 		postList:         l,
+		tagList:          newTagList(delegate),
 		viewport:         vp,
+		opts:             opts,
+		reportInput:      ri,
+		jumpInput:        ji,
+		prefs:            prefs,
+		keys:             keys,
+		renderCache:      make(map[string]string),
+		expandedBlocks:   make(map[string]map[int]bool),
+		revealedSpoilers: make(map[string]map[int]bool),
+		shownFootnotes:   make(map[string]bool),
+		rng:              rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
+		listDelegate:     numbered,
 	}
 }
 
+// glamourStyles are the built-in styles the theme key cycles through. "auto"
+// picks dark or light based on the terminal background.
+var glamourStyles = []string{"auto", "dark", "light", "dracula", "tokyo-night", "pink", "ascii", "notty"}
+
 // --- GitHub Fetching Logic ---
 const (
-	repoOwner = "SpaceCoastDevs"
-	repoName  = "space-coast.dev"
-	repoAPIPath = "src/content/post"
-	githubAPIContentsURLFormat = "https://api.github.com/repos/%s/%s/contents/%s"
+	defaultRepoOwner           = "SpaceCoastDevs"
+	defaultRepoName            = "space-coast.dev"
+	defaultRepoPath            = "src/content/post"
+	githubAPIBase              = "https://api.github.com"
+	githubAPIContentsURLFormat = "%s/repos/%s/%s/contents/%s"
 )
 
+// repoConfig says which GitHub repository directory posts are read from. Set
+// from --owner, --repo and --path.
+type repoConfig struct {
+	owner   string
+	name    string
+	path    string
+	apiBase string // The GitHub API, or a fixture server standing in for it; --gist reads from it too
+}
+
+func defaultRepoConfig() repoConfig {
+	return repoConfig{owner: defaultRepoOwner, name: defaultRepoName, path: defaultRepoPath, apiBase: githubAPIBase}
+}
+
+// contentsURL is the API listing of the posts directory.
+func (r repoConfig) contentsURL() string {
+	return fmt.Sprintf(githubAPIContentsURLFormat, r.apiBase, r.owner, r.name, strings.Trim(r.path, "/"))
+}
+
 // GitHubContent struct to unmarshal the JSON response from GitHub API
 type GitHubContent struct {
 	Name        string `json:"name"`
@@ -148,127 +490,499 @@ type GitHubContent struct {
 // 	Type        string `json:"type"`         // "file" or "dir"
 // }
 
-// fetchPostsCmd simulates fetching and parsing posts.
-// WARNING: This version uses a hardcoded list of file URLs.
-// A real implementation would first query the GitHub API to get the list of .mdx files.
-func fetchPostsCmd() tea.Cmd {
+// fetchPostsCmd fetches and parses every post in the repo directory, telling
+// progress of each file done. Posts that loaded come back even when others
+// failed, whose errors are logged.
+func fetchPostsCmd(repo repoConfig, client fetchClient, progress progressFunc) tea.Cmd {
 	return func() tea.Msg {
-		var posts []PostMetadata
-		client := &http.Client{Timeout: 20 * time.Second} // Increased timeout for multiple requests
-		var firstError error
+		posts, err := fetchRepoPosts(client, repo, progress)
+		if len(posts) == 0 && err != nil {
+			return postsLoadedMsg{posts: nil, err: err}
+		}
+		// If there were non-critical errors for some files but others loaded, we still return the loaded posts.
+		// The individual errors are logged.
+		return postsLoadedMsg{posts: posts, err: nil}
+	}
+}
+
+// fetchRepoPosts loads every post in the repo directory, newest first. When
+// only some files fail, the posts that loaded come back along with the error
+// of the first that didn't.
+func fetchRepoPosts(client fetchClient, repo repoConfig, progress progressFunc) ([]PostMetadata, error) {
+	// 1. Fetch directory listing from GitHub API, every page of it
+	contents, err := fetchContents(client, repo.contentsURL())
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	contents = dedupeContents(contents)
+
+	// 2. Fetch the .mdx files, a few at a time, and parse their frontmatter
+	posts, firstError := fetchPostFiles(client, contents, progress)
+
+	posts = dedupePosts(posts)
 
-		// 1. Fetch directory listing from GitHub API
-		apiURL := fmt.Sprintf(githubAPIContentsURLFormat, repoOwner, repoName, repoAPIPath)
-		req, err := http.NewRequestWithContext(context.Background(), "GET", apiURL, nil)
+	// Sort posts by PublishDate in descending order
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].PublishDate.After(posts[j].PublishDate)
+	})
+
+	if len(posts) == 0 && firstError != nil {
+		return nil, fmt.Errorf("failed to load any posts, first error: %w", firstError)
+	}
+	return posts, firstError
+}
+
+// maxContentsPages stops fetchContents following next links that never end.
+const maxContentsPages = 100
+
+// fetchContents reads a directory listing from the GitHub contents API,
+// following the Link header's next page until there is none, as the API
+// pages large directories.
+func fetchContents(client fetchClient, apiURL string) ([]GitHubContent, error) {
+	var contents []GitHubContent
+	for page := 0; apiURL != ""; page++ {
+		if page == maxContentsPages {
+			return nil, fmt.Errorf("fetching API %s: more than %d pages", apiURL, maxContentsPages)
+		}
+		pageContents, next, err := fetchContentsPage(client, apiURL)
 		if err != nil {
-			errMsg := fmt.Errorf("creating API request for %s: %w", apiURL, err)
-			log.Println(errMsg)
-			return postsLoadedMsg{posts: nil, err: errMsg}
+			return nil, err
 		}
+		contents = append(contents, pageContents...)
+		apiURL = next
+	}
+	return contents, nil
+}
 
-		apiResp, err := client.Do(req)
-		if err != nil {
-			errMsg := fmt.Errorf("fetching API %s: %w", apiURL, err)
-			log.Println(errMsg)
-			return postsLoadedMsg{posts: nil, err: errMsg}
+// fetchContentsPage reads one page of a directory listing, returning the URL
+// of the next page too, or "" on the last.
+func fetchContentsPage(client fetchClient, apiURL string) ([]GitHubContent, string, error) {
+	req, err := http.NewRequestWithContext(context.Background(), "GET", apiURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating API request for %s: %w", apiURL, err)
+	}
+	authorizeGitHub(req)
+
+	apiResp, err := doWithRetry(client, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching API %s: %w", apiURL, err)
+	}
+	defer apiResp.Body.Close()
+
+	if apiResp.StatusCode != http.StatusOK {
+		if limited := rateLimitError(apiResp); limited != nil {
+			return nil, "", fmt.Errorf("fetching API %s: %w", apiURL, limited)
 		}
-		defer apiResp.Body.Close()
+		return nil, "", fmt.Errorf("fetching API %s: status %s", apiURL, apiResp.Status)
+	}
+
+	apiBody, err := io.ReadAll(apiResp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading API response body from %s: %w", apiURL, err)
+	}
+
+	contents, err := decodeContents(apiBody)
+	if err != nil {
+		return nil, "", fmt.Errorf("unmarshalling API JSON from %s: %w", apiURL, err)
+	}
+	return contents, nextPageURL(apiResp.Header.Get("Link")), nil
+}
 
-		if apiResp.StatusCode != http.StatusOK {
-			errMsg := fmt.Errorf("fetching API %s: status %s", apiURL, apiResp.Status)
-			log.Println(errMsg)
-			return postsLoadedMsg{posts: nil, err: errMsg}
+// nextPageURL picks the rel="next" URL out of a Link header such as
+// `<https://api.github.com/...&page=2>; rel="next", <...>; rel="last"`.
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok {
+			continue
 		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
 
-		apiBody, err := io.ReadAll(apiResp.Body) // Replaced ioutil.ReadAll with io.ReadAll
-		if err != nil {
-			errMsg := fmt.Errorf("reading API response body from %s: %w", apiURL, err)
-			log.Println(errMsg)
-			return postsLoadedMsg{posts: nil, err: errMsg}
+// fetchWorkers caps how many post files are downloaded at once.
+const fetchWorkers = 8
+
+// fetchPostFiles downloads and parses the .mdx files of a directory listing,
+// fetchWorkers at a time. Posts come back in listing order, so which of two
+// duplicates dedupePosts keeps doesn't depend on download times. The error
+// is the one of the earliest file in the listing that failed, except that a
+// rate limit error wins as it explains the failures after it. progress is
+// told of each file done out of all those to fetch.
+func fetchPostFiles(client fetchClient, contents []GitHubContent, progress progressFunc) ([]PostMetadata, error) {
+	var files []int // Indexes of the contents to fetch
+	for i, content := range contents {
+		if content.Type != "file" || !strings.HasSuffix(content.Name, ".mdx") {
+			continue
 		}
+		if content.DownloadURL == "" {
+			log.Printf("Skipping file %s as it has no download_url", content.Name)
+			continue
+		}
+		files = append(files, i)
+	}
+	progress.report(0, len(files))
 
-		var contents []GitHubContent
-		err = json.Unmarshal(apiBody, &contents)
-		if err != nil {
-			errMsg := fmt.Errorf("unmarshalling API JSON from %s: %w", apiURL, err)
-			log.Println(errMsg)
-			return postsLoadedMsg{posts: nil, err: errMsg}
-		}
-
-		// 2. For each .mdx file, fetch its content and parse frontmatter
-		for _, content := range contents {
-			if content.Type == "file" && strings.HasSuffix(content.Name, ".mdx") && content.DownloadURL != "" {
-				fileURL := content.DownloadURL
-				fileReq, err := http.NewRequestWithContext(context.Background(), "GET", fileURL, nil)
-				if err != nil {
-					log.Printf("Error creating request for %s: %v", fileURL, err)
-					if firstError == nil { firstError = fmt.Errorf("creating request for %s: %w", fileURL, err) }
-					continue
-				}
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		fetched    []indexedPost
+		done       int
+		firstError error
+		firstIndex int
+	)
+	sem := make(chan struct{}, fetchWorkers)
+	for _, i := range files {
+		content := contents[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			meta, err := fetchPostFile(client, content)
 
-				resp, err := client.Do(fileReq)
-				if err != nil {
-					log.Printf("Error fetching %s: %v", fileURL, err)
-					if firstError == nil { firstError = fmt.Errorf("fetching %s: %w", fileURL, err) }
-					continue
-				}
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			progress.report(done, len(files))
+			if err == nil {
+				fetched = append(fetched, indexedPost{i, meta})
+				return
+			}
+			limited, firstLimited := errors.Is(err, errRateLimited), errors.Is(firstError, errRateLimited)
+			if firstError == nil || limited && !firstLimited || limited == firstLimited && i < firstIndex {
+				firstError, firstIndex = err, i
+			}
+		}()
+	}
+	wg.Wait()
 
-				if resp.StatusCode != http.StatusOK {
-					log.Printf("Error fetching %s: status %s", fileURL, resp.Status)
-					if firstError == nil { firstError = fmt.Errorf("fetching %s: status %s", fileURL, resp.Status) }
-					resp.Body.Close()
-					continue
-				}
+	sort.Slice(fetched, func(a, b int) bool { return fetched[a].index < fetched[b].index })
+	posts := make([]PostMetadata, len(fetched))
+	for i, f := range fetched {
+		posts[i] = f.post
+	}
+	return posts, firstError
+}
 
-				body, err := io.ReadAll(resp.Body) // Replaced ioutil.ReadAll with io.ReadAll
-				resp.Body.Close()
-				if err != nil {
-					log.Printf("Error reading body for %s: %v", fileURL, err)
-					if firstError == nil { firstError = fmt.Errorf("reading body for %s: %w", fileURL, err) }
-					continue
-				}
+// indexedPost is a fetched post and its position in the directory listing.
+type indexedPost struct {
+	index int
+	post  PostMetadata
+}
 
-				contentStr := string(body)
-				parts := strings.SplitN(contentStr, "---", 3)
-				if len(parts) < 3 {
-					log.Printf("Could not find frontmatter in %s", fileURL)
-					if firstError == nil { firstError = fmt.Errorf("no frontmatter in %s", fileURL) }
-					continue
-				}
+// fetchPostFile downloads and parses one post file from a directory listing.
+func fetchPostFile(client fetchClient, content GitHubContent) (PostMetadata, error) {
+	fileURL := content.DownloadURL
+	fileReq, err := http.NewRequestWithContext(context.Background(), "GET", fileURL, nil)
+	if err != nil {
+		log.Printf("Error creating request for %s: %v", fileURL, err)
+		return PostMetadata{}, fmt.Errorf("creating request for %s: %w", fileURL, err)
+	}
+	authorizeGitHub(fileReq)
 
-				var meta PostMetadata
-				err = yaml.Unmarshal([]byte(parts[1]), &meta)
-				if err != nil {
-					log.Printf("Error unmarshalling YAML for %s: %v", fileURL, err)
-					if firstError == nil { firstError = fmt.Errorf("unmarshalling YAML for %s: %w", fileURL, err) }
-					continue
-				}
-				meta.Content = strings.TrimSpace(parts[2]) // Store the main content
-				posts = append(posts, meta)
-			} else if content.Type == "file" && strings.HasSuffix(content.Name, ".mdx") {
-				log.Printf("Skipping file %s as it has no download_url", content.Name)
+	resp, err := doWithRetry(client, fileReq)
+	if err != nil {
+		log.Printf("Error fetching %s: %v", fileURL, err)
+		return PostMetadata{}, fmt.Errorf("fetching %s: %w", fileURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Error fetching %s: status %s", fileURL, resp.Status)
+		if limited := rateLimitError(resp); limited != nil {
+			return PostMetadata{}, fmt.Errorf("fetching %s: %w", fileURL, limited)
+		}
+		return PostMetadata{}, fmt.Errorf("fetching %s: status %s", fileURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Printf("Error reading body for %s: %v", fileURL, err)
+		return PostMetadata{}, fmt.Errorf("reading body for %s: %w", fileURL, err)
+	}
+
+	meta, err := parsePost(fileURL, body)
+	if err != nil {
+		log.Println(err)
+		return PostMetadata{}, err
+	}
+	if meta.Slug == "" {
+		// As the site does, so same-titled posts don't collide on their title
+		meta.Slug = strings.TrimSuffix(content.Name, ".mdx")
+	}
+	meta.SourceURL = fileURL
+	meta.SourcePath = content.Path
+	return meta, nil
+}
+
+// dedupeContents drops repeated directory entries with the same path, keeping the first.
+func dedupeContents(contents []GitHubContent) []GitHubContent {
+	seen := make(map[string]bool, len(contents))
+	unique := contents[:0:0]
+	for _, c := range contents {
+		if seen[c.Path] {
+			log.Printf("Dropping duplicate directory entry %s", c.Path)
+			continue
+		}
+		seen[c.Path] = true
+		unique = append(unique, c)
+	}
+	return unique
+}
+
+// dedupePosts drops posts whose slug (or title, when there's no slug) was already seen.
+func dedupePosts(posts []PostMetadata) []PostMetadata {
+	seen := make(map[string]bool, len(posts))
+	unique := posts[:0:0]
+	for _, p := range posts {
+		if seen[p.key()] {
+			log.Printf("Dropping duplicate post %q", p.key())
+			continue
+		}
+		seen[p.key()] = true
+		unique = append(unique, p)
+	}
+	return unique
+}
+
+// publicPosts returns the posts not marked private. Anything published outside
+// the TUI, such as a feed or an API, must go through it.
+func publicPosts(posts []PostMetadata) []PostMetadata {
+	public := posts[:0:0]
+	for _, p := range posts {
+		if !p.Private {
+			public = append(public, p)
+		}
+	}
+	return public
+}
+
+// disambiguateTitles sets ListTitle on posts whose title another post shares,
+// appending the date, or the slug when the dates are the same too, so lists
+// showing only titles can tell them apart. Dates are written with formatDate.
+func disambiguateTitles(posts []PostMetadata, formatDate dateFormat) {
+	byTitle := make(map[string][]int)
+	for i, p := range posts {
+		byTitle[p.PostTitle] = append(byTitle[p.PostTitle], i)
+	}
+	for _, same := range byTitle {
+		if len(same) < 2 {
+			continue
+		}
+		dates := make(map[string]int)
+		for _, i := range same {
+			dates[formatDate(posts[i].PublishDate)]++
+		}
+		for _, i := range same {
+			p := &posts[i]
+			if date := formatDate(p.PublishDate); dates[date] == 1 {
+				p.ListTitle = fmt.Sprintf("%s (%s)", p.PostTitle, date)
+			} else if p.Slug != "" {
+				p.ListTitle = fmt.Sprintf("%s (%s)", p.PostTitle, p.Slug)
 			}
 		}
+	}
+}
+
+var errNoFrontmatter = errors.New("no frontmatter")
+
+var errPathIsFile = errors.New("path points to a file, not a directory of posts")
 
-		// Sort posts by PublishDate in descending order
-		sort.Slice(posts, func(i, j int) bool {
-			return posts[i].PublishDate.After(posts[j].PublishDate)
-		})
+// decodeContents decodes a contents API listing. For a file the API answers
+// with a single object instead of an array, which is reported as errPathIsFile
+// rather than a confusing type mismatch.
+func decodeContents(body []byte) ([]GitHubContent, error) {
+	var contents []GitHubContent
+	err := json.Unmarshal(body, &contents)
+	if err == nil {
+		return contents, nil
+	}
+	var single GitHubContent
+	if json.Unmarshal(body, &single) == nil && single.Type != "" && single.Type != "dir" {
+		return nil, fmt.Errorf("%s: %w", single.Path, errPathIsFile)
+	}
+	return nil, err
+}
+
+// loadHomePage reads the operator's front page. Frontmatter is optional; without
+// it the whole file is the body.
+func loadHomePage(path string) (PostMetadata, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return PostMetadata{}, err
+	}
+	home, err := parsePost(path, body)
+	if errors.Is(err, errNoFrontmatter) {
+		home = PostMetadata{Content: strings.TrimSpace(string(body))}
+		home.ReadingMinutes = readingMinutes(home.Content)
+	} else if err != nil {
+		return PostMetadata{}, err
+	}
+	if home.PostTitle == "" {
+		home.PostTitle = "Home"
+	}
+	return home, nil
+}
+
+// parsePost splits a post file into its YAML frontmatter and body. source names
+// the file in errors and logs.
+func parsePost(source string, body []byte) (PostMetadata, error) {
+	var meta PostMetadata
+	front, content, format, firstLine, ok := splitFrontmatterAt(string(body))
+	if !ok {
+		return meta, fmt.Errorf("%w in %s", errNoFrontmatter, source)
+	}
+
+	if err := decodeFrontmatter(format, front, firstLine, &meta); err != nil {
+		return meta, fmt.Errorf("unmarshalling %s for %s: %w", format, source, err)
+	}
+	if meta.AccentColor != "" && !isValidColor(meta.AccentColor) {
+		log.Printf("Ignoring invalid accentColor %q in %s", meta.AccentColor, source)
+		meta.AccentColor = ""
+	}
+	meta.Content = strings.TrimSpace(content) // Store the main content
+	meta.ReadingMinutes = readingMinutes(meta.Content)
+	meta.PlainText = plainText(meta.Content)
+	if len(meta.Tags) == 0 {
+		meta.SuggestedTags = suggestTags(meta.Content)
+	}
+	return meta, nil
+}
 
-		if len(posts) == 0 && firstError != nil {
-			return postsLoadedMsg{posts: nil, err: fmt.Errorf("failed to load any posts, first error: %w", firstError)}
+// splitFrontmatter separates a leading frontmatter block from the document body.
+// Blank lines, a byte order mark and HTML comments may precede the block, whose
+// opening and closing fences must be "---" (YAML) or "+++" (TOML) on lines of
+// their own; a "---" later in the body (a horizontal rule, say) is left alone.
+// A JSON object opening the document is frontmatter too.
+func splitFrontmatter(content string) (front, body string, ok bool) {
+	front, body, _, _, ok = splitFrontmatterAt(content)
+	return front, body, ok
+}
+
+// splitFrontmatterAt is splitFrontmatter that also returns the frontmatter's
+// format and the line of the file (counting from 1) where its first line is,
+// to locate errors.
+func splitFrontmatterAt(content string) (front, body string, format frontmatterFormat, line int, ok bool) {
+	lines := strings.SplitAfter(strings.TrimPrefix(content, "\ufeff"), "\n")
+	fence := ""
+	isFence := func(line string) bool {
+		return strings.TrimRight(line, " \t\r\n") == fence
+	}
+
+	start := 0
+	inComment := false
+	for ; start < len(lines); start++ {
+		line := strings.TrimSpace(lines[start])
+		if inComment {
+			inComment = !strings.Contains(line, "-->")
+			continue
 		}
-		// If there were non-critical errors for some files but others loaded, we still return the loaded posts.
-		// The individual errors are logged.
-		return postsLoadedMsg{posts: posts, err: nil}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "<!--") {
+			inComment = !strings.Contains(line, "-->")
+			continue
+		}
+		break
+	}
+	if start == len(lines) {
+		return "", "", 0, 0, false
+	}
+	switch opening := strings.TrimRight(lines[start], " \t\r\n"); {
+	case opening == "---":
+		fence, format = opening, yamlFrontmatter
+	case opening == "+++":
+		fence, format = opening, tomlFrontmatter
+	case strings.HasPrefix(opening, "{"):
+		// The object ends where the JSON does; an MDX {/* comment */} isn't one
+		rest := strings.Join(lines[start:], "")
+		dec := json.NewDecoder(strings.NewReader(rest))
+		var object json.RawMessage
+		if err := dec.Decode(&object); err != nil {
+			return "", "", 0, 0, false
+		}
+		body = rest[dec.InputOffset():]
+		if eol := strings.IndexByte(body, '\n'); eol >= 0 && strings.TrimSpace(body[:eol]) == "" {
+			body = body[eol+1:]
+		}
+		return string(object), body, jsonFrontmatter, start + 1, true
+	default:
+		return "", "", 0, 0, false
+	}
+
+	for end := start + 1; end < len(lines); end++ {
+		if isFence(lines[end]) {
+			front = strings.Join(lines[start+1:end], "")
+			body = strings.Join(lines[end+1:], "")
+			return front, body, format, start + 2, true
+		}
+	}
+	return "", "", 0, 0, false
+}
+
+var yamlLineRe = regexp.MustCompile(`\bline (\d+)`)
+
+// yamlErrorAt rewrites the frontmatter-relative "line N" locations in a YAML
+// or TOML error so they count from the top of the file, where firstLine is the file
+// line the frontmatter starts on.
+func yamlErrorAt(err error, firstLine int) string {
+	return yamlLineRe.ReplaceAllStringFunc(err.Error(), func(match string) string {
+		n, _ := strconv.Atoi(yamlLineRe.FindStringSubmatch(match)[1])
+		return fmt.Sprintf("line %d", n+firstLine-1)
+	})
+}
+
+// fetchCmd loads posts from the source opts configure, telling progress of
+// each file done and retrying of each retry.
+func fetchCmd(opts options, progress progressFunc, retrying retryFunc) tea.Cmd {
+	client := newFetchClient(opts)
+	client.retrying = retrying
+	fetch := fetchPostsCmd(opts.repo, client, progress)
+	if opts.gistID != "" {
+		fetch = fetchGistCmd(opts.repo.apiBase, opts.gistID, client, progress)
 	}
+	if path, err := cachePath(opts.gistID, opts.repo); err != nil {
+		log.Printf("Error locating the post cache: %v", err)
+	} else {
+		if opts.incremental && opts.gistID == "" {
+			fetch = incrementalFetchCmd(opts.repo, client, path, opts.refresh, progress)
+		}
+		fetch = withCache(fetch, path, opts.refresh)
+	}
+	if opts.metadataOnly {
+		return metadataOnly(fetch)
+	}
+	return fetch
 }
 
 func (m model) Init() tea.Cmd {
 	// We need to send a WindowSizeMsg to initialize the viewport correctly after the UI is up.
 	// However, tea.EnterAltScreen and initial tick are also important.
 	// A common pattern is to handle initial sizing in the first WindowSizeMsg.
-	return tea.Batch(tick(), tea.EnterAltScreen)
+	// Not every terminal reports its size unasked, so query it and stop waiting
+	// after sizeTimeout rather than sit on "Initializing..." forever.
+	cmds := []tea.Cmd{tick(), tea.WindowSize(), tea.Tick(sizeTimeout, func(time.Time) tea.Msg {
+		return sizeTimeoutMsg{}
+	})}
+	if !m.opts.noAltScreen {
+		cmds = append(cmds, tea.EnterAltScreen)
+	}
+	if m.opts.sshMode {
+		cmds = append(cmds, maintenanceCheck())
+	}
+	if m.currentScreen == splashScreen && m.typing() {
+		cmds = append(cmds, typeTick())
+	}
+	return tea.Batch(cmds...)
 }
 
 func tick() tea.Cmd {
@@ -277,7 +991,19 @@ func tick() tea.Cmd {
 	})
 }
 
+// Update handles msg, then has the next chunk of a long post rendered if
+// one is waiting.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if m, ok := next.(model); ok {
+		if chunk := m.nextChunkCmd(); chunk != nil {
+			return m, tea.Batch(cmd, chunk)
+		}
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -285,118 +1011,957 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+		// The post detail screen has a header above the viewport, and the
+		// related posts and footer below it
+		chromeHeight := 3
 		if !m.ready { // First WindowSizeMsg, set up viewport
-			// For listScreen, we need full height minus space for footer
-			footerHeight := 2 // Space for footer help text
-			m.viewport = viewport.New(msg.Width, msg.Height-footerHeight)
+			m.viewport = viewport.New(msg.Width, msg.Height-chromeHeight)
 			m.ready = true
+			// If posts arrived before the first size, this is where they get rendered
+			m.rerenderViewport()
 		} else {
-			// For listScreen, we need full height minus space for footer
-			footerHeight := 2 // Space for footer help text
 			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - footerHeight
+			m.viewport.Height = msg.Height - chromeHeight
+
+			// Dragging a window edge sends a flood of sizes; re-wrap the post only
+			// once they settle instead of running glamour for every one
+			m.resizeSeq++
+			seq := m.resizeSeq
+			cmds = append(cmds, tea.Tick(resizeDebounce, func(time.Time) tea.Msg {
+				return resizeRenderMsg{seq: seq}
+			}))
 		}
 
 		m.postList.SetWidth(msg.Width)
 		m.postList.SetHeight(msg.Height) // List takes full height when active
+		m.tagList.SetSize(msg.Width, msg.Height)
 
 	case tea.KeyMsg:
-		switch m.currentScreen {
-		case splashScreen:
-			switch msg.String() {
-			case "q", "esc", "ctrl+c":
+		// Sessions stay connected during maintenance but can only leave
+		if maintenanceMode.Load() {
+			if msg.String() == "ctrl+c" || msg.String() == "q" {
 				return m, tea.Quit
-			case "enter":
-				m.currentScreen = listScreen
-				m.loadingPosts = true
-				m.postsError = nil
-				m.postList.SetItems([]list.Item{}) 
-				cmds = append(cmds, fetchPostsCmd())
 			}
-		case listScreen:
-			switch msg.String() {
-			case "q", "esc":
+			return m, nil
+		}
+
+		// Any key finishes typing out the splash
+		if m.currentScreen == splashScreen && m.typing() {
+			if msg.String() == "ctrl+c" {
 				return m, tea.Quit
-			case "b", "backspace":
-				m.currentScreen = splashScreen
-				m.showFlashMessage = true
-				m.postsError = nil
-				cmds = append(cmds, tick())
-			case "up", "k":
-				m.viewport.ScrollUp(1)
-			case "down", "j":
-				m.viewport.ScrollDown(1)
-			case "pageup":
-				m.viewport.ScrollUp(m.viewport.Height)
-			case "pagedown":
-				m.viewport.ScrollDown(m.viewport.Height)
-			case "home":
-				m.viewport.GotoTop()
-			case "end":
-				m.viewport.GotoBottom()
 			}
+			m.typed = m.splashLength()
+			return m, nil
 		}
 
-	case tickMsg:
-		if m.currentScreen == splashScreen {
-			m.showFlashMessage = !m.showFlashMessage
-			cmds = append(cmds, tick())
+		if m.reporting {
+			switch msg.String() {
+			case "enter":
+				m.reporting = false
+				m.lastReport = time.Now() // The cooldown counts from every attempt
+				report := postReport{
+					Slug:       m.selectedPost.key(),
+					Title:      m.selectedPost.PostTitle,
+					Reason:     strings.TrimSpace(m.reportInput.Value()),
+					ReportedAt: m.lastReport,
+				}
+				cmds = append(cmds, sendReportCmd(m.opts.reportWebhook, report, m.opts.minTLS), m.setStatus("Sending report..."))
+			case "esc":
+				m.reporting = false
+			default:
+				var cmd tea.Cmd
+				m.reportInput, cmd = m.reportInput.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
 		}
 
-	case postsLoadedMsg:
-		m.loadingPosts = false
-		if msg.err != nil {
-			m.postsError = msg.err
-			log.Printf("Error in postsLoadedMsg: %v", msg.err)
-			m.postList.SetItems([]list.Item{}) 
-		} else {
-			items := make([]list.Item, len(msg.posts))
-			for i, p := range msg.posts {
-				items[i] = p 
-			}
-			m.postList.SetItems(items)
-			m.postsError = nil
-			
-			// Set viewport content with the latest post
-			if len(msg.posts) > 0 {
-				latestPost := msg.posts[0]
-				postContent := transformLinksToFootnotes(stripTags(latestPost.Content))
-				glowRenderer, err := glamour.NewTermRenderer(
-					glamour.WithAutoStyle(),
-					glamour.WithWordWrap(m.viewport.Width-2),
-				)
-				if err != nil {
-					log.Printf("Error creating glamour renderer: %v", err)
-					m.viewport.SetContent("Error initializing renderer.")
-				} else {
-					formattedContent, err := glowRenderer.Render(postContent)
-					if err != nil {
-						log.Printf("Error rendering markdown: %v", err)
-						m.viewport.SetContent("Error rendering content.")
-					} else {
-						m.viewport.SetContent(formattedContent)
-					}
-				}
+		if m.jumping {
+			switch msg.String() {
+			case "enter":
+				m.jumping = false
+				cmds = append(cmds, m.jumpToDate(m.jumpInput.Value()))
+			case "esc":
+				m.jumping = false
+			default:
+				var cmd tea.Cmd
+				m.jumpInput, cmd = m.jumpInput.Update(msg)
+				cmds = append(cmds, cmd)
 			}
+			return m, tea.Batch(cmds...)
 		}
-	}
-	return m, tea.Batch(cmds...)
-}
 
-// Helper views for header/footer of postDetailScreen
-func (m model) headerView() string {
+		// While a filter is being typed every key belongs to the list
+		if m.currentScreen == listScreen && m.postList.SettingFilter() {
+			var cmd tea.Cmd
+			m.postList, cmd = m.postList.Update(msg)
+			return m, m.debounceFilter(cmd)
+		}
+		if m.currentScreen == tagsScreen && m.tagList.SettingFilter() {
+			var cmd tea.Cmd
+			m.tagList, cmd = m.tagList.Update(msg)
+			return m, cmd
+		}
+
+		if m.showHelp {
+			// Any key closes help; only quitting also acts
+			m.showHelp = false
+			if key.Matches(msg, m.keys.Exit.Binding) {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if key.Matches(msg, m.keys.Help.Binding) {
+			m.showHelp = true
+			return m, nil
+		}
+
+		// q and esc back out one screen at a time and only quit from the top;
+		// Q and ctrl+c quit from anywhere
+		if key.Matches(msg, m.keys.Exit.Binding) {
+			return m, tea.Quit
+		}
+
+		switch m.currentScreen {
+		case archiveScreen:
+			switch {
+			case key.Matches(msg, m.keys.ArchiveBack.Binding):
+				m.currentScreen = listScreen
+			case key.Matches(msg, m.keys.Up.Binding):
+				m.archive.move(-1)
+			case key.Matches(msg, m.keys.Down.Binding):
+				m.archive.move(1)
+			case key.Matches(msg, m.keys.ArchiveFold.Binding):
+				m.archive.fold()
+			case key.Matches(msg, m.keys.ArchiveOpen.Binding):
+				if p, ok := m.archive.toggle(); ok {
+					cmds = append(cmds, m.showPost(p))
+				}
+			}
+		case firstRunScreen:
+			switch {
+			case key.Matches(msg, m.keys.Quit.Binding):
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.Theme.Binding):
+				m.cycleGlamourStyle()
+			case key.Matches(msg, m.keys.ReadingSize.Binding):
+				m.prefs.ReadingSize = (m.readingSize() + 1) % numReadingSizes
+			case key.Matches(msg, m.keys.Continue.Binding):
+				m.prefs.FirstRunDone = true
+				m.currentScreen = splashScreen
+				cmds = append(cmds, saveSettingsCmd(m.prefs), tick())
+				if m.typing() {
+					cmds = append(cmds, typeTick())
+				}
+			}
+		case splashScreen:
+			switch {
+			case key.Matches(msg, m.keys.Quit.Binding):
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.Continue.Binding):
+				m.currentScreen = listScreen
+				cmds = append(cmds, m.loadPosts())
+			case key.Matches(msg, m.keys.Today.Binding):
+				m.currentScreen = todayScreen
+				cmds = append(cmds, m.loadPosts())
+			case key.Matches(msg, m.keys.Random.Binding):
+				m.currentScreen = listScreen
+				m.pendingRandom = true
+				cmds = append(cmds, m.loadPosts())
+			}
+		case statsScreen:
+			if key.Matches(msg, m.keys.StatsBack.Binding) {
+				m.currentScreen = listScreen
+			}
+		case tagsScreen:
+			switch {
+			case msg.String() == "esc" && m.tagList.FilterState() == list.FilterApplied:
+				var cmd tea.Cmd
+				m.tagList, cmd = m.tagList.Update(msg)
+				cmds = append(cmds, cmd)
+			case key.Matches(msg, m.keys.TagsBack.Binding):
+				m.filterByTag("")
+				m.currentScreen = listScreen
+			case key.Matches(msg, m.keys.TagPick.Binding):
+				if t, ok := m.tagList.SelectedItem().(tagItem); ok {
+					m.filterByTag(t.name)
+					m.currentScreen = listScreen
+				}
+			default:
+				var cmd tea.Cmd
+				m.tagList, cmd = m.tagList.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+		case todayScreen:
+			switch {
+			case key.Matches(msg, m.keys.TodayBack.Binding):
+				m.currentScreen = listScreen
+			case key.Matches(msg, m.keys.Up.Binding):
+				m.today.move(-1)
+			case key.Matches(msg, m.keys.Down.Binding):
+				m.today.move(1)
+			case key.Matches(msg, m.keys.Open.Binding):
+				if p, ok := m.today.selected(); ok {
+					cmds = append(cmds, m.showPost(p))
+				}
+			}
+		case listScreen:
+			switch {
+			case msg.String() == "esc" && m.postList.FilterState() == list.FilterApplied:
+				// Esc clears an applied filter before it goes back
+				var cmd tea.Cmd
+				m.postList, cmd = m.postList.Update(msg)
+				cmds = append(cmds, cmd)
+			case msg.String() == "esc" && m.tagFilter != "":
+				// And then the tag the list is limited to
+				m.filterByTag("")
+			case key.Matches(msg, m.keys.Back.Binding):
+				m.currentScreen = splashScreen
+				m.showFlashMessage = true
+				m.postsError = nil
+				cmds = append(cmds, tick())
+			case key.Matches(msg, m.keys.Open.Binding):
+				if p, ok := m.postList.SelectedItem().(PostMetadata); ok {
+					cmds = append(cmds, m.showPost(p))
+				}
+			case key.Matches(msg, m.keys.StatusBar.Binding):
+				m.postList.SetShowStatusBar(!m.postList.ShowStatusBar())
+				m.prefs.HideStatusBar = !m.postList.ShowStatusBar()
+				if !m.opts.sshMode {
+					cmds = append(cmds, saveSettingsCmd(m.prefs))
+				}
+			case key.Matches(msg, m.keys.Numbers.Binding):
+				m.listDelegate.numbered = !m.listDelegate.numbered
+				m.postList.SetDelegate(m.listDelegate)
+			case key.Matches(msg, m.keys.Sort.Binding):
+				m.sortMode = (m.sortMode + 1) % numSortModes
+				m.applySort()
+			case key.Matches(msg, m.keys.Newest.Binding):
+				cmds = append(cmds, m.jumpToEnd(true))
+			case key.Matches(msg, m.keys.Oldest.Binding):
+				cmds = append(cmds, m.jumpToEnd(false))
+			case key.Matches(msg, m.keys.Archive.Binding):
+				m.currentScreen = archiveScreen
+			case key.Matches(msg, m.keys.Today.Binding):
+				m.today = newTodayView(m.posts, time.Now())
+				m.currentScreen = todayScreen
+			case key.Matches(msg, m.keys.Stats.Binding):
+				m.currentScreen = statsScreen
+			case key.Matches(msg, m.keys.Tags.Binding):
+				m.openTagMenu()
+			case key.Matches(msg, m.keys.Random.Binding):
+				cmds = append(cmds, m.openRandomPost())
+			case key.Matches(msg, m.keys.Web.Binding):
+				if p, ok := m.postList.SelectedItem().(PostMetadata); ok {
+					cmds = append(cmds, m.openWebCmd(p))
+				}
+			case key.Matches(msg, m.keys.JumpDate.Binding):
+				m.jumping = true
+				m.jumpInput.SetValue("")
+				cmds = append(cmds, m.jumpInput.Focus())
+			default:
+				// Moving, paging and filtering are the list's own keys
+				var cmd tea.Cmd
+				m.postList, cmd = m.postList.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+		case postDetailScreen:
+			switch {
+			case key.Matches(msg, m.keys.Close.Binding):
+				m.currentScreen = listScreen
+			case key.Matches(msg, m.keys.Up.Binding):
+				m.viewport.ScrollUp(m.opts.scrollLines)
+			case key.Matches(msg, m.keys.Down.Binding):
+				m.viewport.ScrollDown(m.opts.scrollLines)
+			case key.Matches(msg, m.keys.PageUp.Binding):
+				m.viewport.ScrollUp(m.viewport.Height)
+			case key.Matches(msg, m.keys.PageDown.Binding):
+				m.viewport.ScrollDown(m.viewport.Height)
+			case key.Matches(msg, m.keys.Top.Binding):
+				m.viewport.GotoTop()
+			case key.Matches(msg, m.keys.Bottom.Binding):
+				m.viewport.GotoBottom()
+			case key.Matches(msg, m.keys.Raw.Binding):
+				m.showRaw = !m.showRaw
+				m.rerenderViewport()
+			case key.Matches(msg, m.keys.Info.Binding):
+				m.hideMetadata = !m.hideMetadata
+				m.rerenderViewport()
+			case key.Matches(msg, m.keys.Expand.Binding):
+				m.expandCodeBlock()
+			case key.Matches(msg, m.keys.Reveal.Binding):
+				m.revealSpoiler()
+			case key.Matches(msg, m.keys.Footnotes.Binding):
+				m.toggleFootnotes()
+			case key.Matches(msg, m.keys.CopyLinks.Binding):
+				cmds = append(cmds, m.copyFootnotesCmd())
+			case key.Matches(msg, m.keys.CopyHTML.Binding):
+				cmds = append(cmds, m.copyHTMLCmd())
+			case key.Matches(msg, m.keys.Source.Binding):
+				cmds = append(cmds, m.openSourceCmd())
+			case key.Matches(msg, m.keys.Web.Binding):
+				if m.selectedPost != nil {
+					cmds = append(cmds, m.openWebCmd(*m.selectedPost))
+				}
+			case key.Matches(msg, m.keys.LineNumbers.Binding):
+				m.lineNumbers = !m.lineNumbers
+				m.rerenderViewport()
+			case key.Matches(msg, m.keys.Follow.Binding):
+				m.follow = !m.follow
+				if m.follow {
+					m.viewport.GotoBottom()
+				}
+			case key.Matches(msg, m.keys.Wrap.Binding):
+				m.noWrap = !m.noWrap
+				m.viewport.SetXOffset(0)
+				m.rerenderViewport()
+				if m.noWrap {
+					cmds = append(cmds, m.setStatus("Wrapping off, ←/→ scroll sideways"))
+				} else {
+					cmds = append(cmds, m.setStatus("Wrapping on"))
+				}
+			case key.Matches(msg, m.keys.Left.Binding):
+				m.viewport.ScrollLeft(hscrollStep)
+			case key.Matches(msg, m.keys.Right.Binding):
+				m.viewport.ScrollRight(hscrollStep)
+			case key.Matches(msg, m.keys.Theme.Binding):
+				m.cycleGlamourStyle()
+				m.rerenderViewport()
+				if !m.opts.sshMode {
+					cmds = append(cmds, saveSettingsCmd(m.prefs))
+				}
+			case key.Matches(msg, m.keys.ReadingSize.Binding):
+				m.prefs.ReadingSize = (m.readingSize() + 1) % numReadingSizes
+				m.rerenderViewport()
+				cmds = append(cmds, m.setStatus("Reading size: "+m.readingSize().String()))
+				if !m.opts.sshMode {
+					cmds = append(cmds, saveSettingsCmd(m.prefs))
+				}
+			case key.Matches(msg, m.keys.RelatedNext.Binding, m.keys.RelatedPrev.Binding):
+				if len(m.related) > 0 {
+					step := 1
+					if key.Matches(msg, m.keys.RelatedPrev.Binding) {
+						step = len(m.related) - 1
+					}
+					if m.relatedCursor < 0 {
+						m.relatedCursor = 0
+					} else {
+						m.relatedCursor = (m.relatedCursor + step) % len(m.related)
+					}
+				}
+			case key.Matches(msg, m.keys.OpenRelated.Binding):
+				if m.relatedCursor >= 0 && m.relatedCursor < len(m.related) {
+					cmds = append(cmds, m.openPost(m.posts[m.related[m.relatedCursor]]))
+				}
+			case key.Matches(msg, m.keys.PostBack.Binding):
+				cmds = append(cmds, m.historyBack())
+			case key.Matches(msg, m.keys.PostForward.Binding):
+				cmds = append(cmds, m.historyForward())
+			case key.Matches(msg, m.keys.Report.Binding):
+				// The binding is disabled entirely unless the operator configured a webhook
+				if m.selectedPost != nil {
+					if ok, reason := m.canReport(time.Now()); ok {
+						m.reporting = true
+						m.reportInput.SetValue("")
+						cmds = append(cmds, m.reportInput.Focus())
+					} else {
+						cmds = append(cmds, m.setStatus(reason))
+					}
+				}
+			}
+		}
+
+	case resizeRenderMsg:
+		if msg.seq == m.resizeSeq {
+			m.rerenderViewport()
+		}
+
+	case sizeTimeoutMsg:
+		if !m.ready {
+			log.Printf("No window size after %s, assuming %dx%d", sizeTimeout, fallbackWidth, fallbackHeight)
+			return m.Update(tea.WindowSizeMsg{Width: fallbackWidth, Height: fallbackHeight})
+		}
+
+	case maintenanceCheckMsg:
+		cmds = append(cmds, maintenanceCheck())
+
+	case clearStatusMsg:
+		if msg.id == m.statusID {
+			m.statusMessage = ""
+		}
+
+	case reportSentMsg:
+		if msg.err != nil {
+			log.Printf("Error sending report: %v", msg.err)
+			cmds = append(cmds, m.setStatus("Report failed, please try again later"))
+		} else {
+			m.reportsSent++ // Only reports that arrived count toward the limit
+			cmds = append(cmds, m.setStatus("Thanks, the report was sent to the moderators"))
+		}
+
+	case postBodyMsg:
+		if m.selectedPost == nil || msg.key != m.selectedPost.key() {
+			break // The reader moved on before the body arrived
+		}
+		m.loadingBody = false
+		if msg.err != nil {
+			log.Printf("Error fetching post body: %v", msg.err)
+			m.setContent("Error loading post: " + msg.err.Error())
+			break
+		}
+		// Only the open post holds its body; m.posts stays metadata-only
+		m.selectedPost.Content = msg.content
+		m.setViewportContent()
+
+	case openedURLMsg:
+		if msg.err != nil {
+			log.Println(msg.err)
+			cmds = append(cmds, m.setStatus("Couldn't open a browser, the link is "+msg.url))
+		} else {
+			cmds = append(cmds, m.setStatus("Opened "+msg.url))
+		}
+
+	case copiedMsg:
+		if errors.Is(msg.err, errTooLargeToCopy) {
+			log.Println(msg.err)
+			cmds = append(cmds, m.setStatus("Too large to copy over SSH: "+msg.what))
+		} else if msg.err != nil {
+			log.Println(msg.err)
+			cmds = append(cmds, m.setStatus("Couldn't copy to the clipboard"))
+		} else {
+			cmds = append(cmds, m.setStatus("Copied "+msg.what))
+		}
+
+	case filterDebounceMsg:
+		if msg.seq == m.filterSeq {
+			cmds = append(cmds, m.pendingFilter)
+			m.pendingFilter = nil
+		}
+
+	case chunkRenderedMsg:
+		m.appendChunk(msg)
+
+	case fetchProgressMsg:
+		// A report read after the posts arrived would bring back a retry that's over
+		if msg.updates == m.fetchProgress.updates && m.loadingPosts {
+			m.fetchProgress = msg
+			cmds = append(cmds, waitForProgress(msg.updates))
+		}
+
+	case skeletonTickMsg:
+		if m.loadingPosts {
+			m.skeletonFrame++
+			cmds = append(cmds, skeletonTick())
+		}
+
+	case typeTickMsg:
+		if m.currentScreen == splashScreen && m.typing() {
+			m.typed += m.typeStep()
+			cmds = append(cmds, typeTick())
+		}
+
+	case tickMsg:
+		if m.currentScreen == splashScreen {
+			m.showFlashMessage = !m.showFlashMessage
+			m.splashFrame++
+			cmds = append(cmds, tick())
+		}
+
+	case postsLoadedMsg:
+		m.loadingPosts = false
+		pendingRandom := m.pendingRandom
+		m.pendingRandom = false
+		if msg.err != nil {
+			m.postsError = msg.err
+			log.Printf("Error in postsLoadedMsg: %v", msg.err)
+			m.postList.SetItems([]list.Item{})
+		} else {
+			m.posts = msg.posts
+			if !m.opts.showPrivate {
+				m.posts = publicPosts(m.posts)
+			}
+			disambiguateTitles(m.posts, m.opts.formatDate)
+			m.relatedIdx = buildRelatedIndex(m.posts)
+			m.archive = newArchiveView(m.posts)
+			m.today = newTodayView(m.posts, time.Now())
+			if !msg.cachedAt.IsZero() {
+				cmds = append(cmds, m.setStatus(offlineNotice(msg.cachedAt, m.opts.formatDate)))
+			}
+			clear(m.renderCache)
+			m.applySort()
+			m.postsError = nil
+
+			if pendingRandom && m.currentScreen == listScreen {
+				cmds = append(cmds, m.openRandomPost())
+			} else if p, ok := m.homePost(); ok && m.currentScreen == listScreen {
+				cmds = append(cmds, m.showPost(p))
+			}
+		}
+
+	default:
+		// The list's own messages, such as filter results
+		var cmd tea.Cmd
+		m.postList, cmd = m.postList.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// loadPosts starts fetching the posts, showing the loading skeleton meanwhile.
+func (m *model) loadPosts() tea.Cmd {
+	m.loadingPosts = true
+	m.postsError = nil
+	m.postList.SetItems([]list.Item{})
+	m.skeletonFrame = 0
+	updates, progress, retrying := newFetchProgress()
+	m.fetchProgress = fetchProgressMsg{updates: updates}
+	fetch := fetchCmd(m.opts, progress, retrying)
+	cmds := []tea.Cmd{func() tea.Msg {
+		defer close(updates)
+		return fetch()
+	}, waitForProgress(updates)}
+	if !m.opts.reduceMotion {
+		cmds = append(cmds, skeletonTick())
+	}
+	return tea.Batch(cmds...)
+}
+
+// applySort refreshes the list items in the active sort order and reflects it in the title.
+func (m *model) applySort() {
+	sorted := make([]PostMetadata, 0, len(m.posts))
+	for _, p := range m.posts {
+		if matchesTag(p, m.tagFilter) {
+			sorted = append(sorted, p)
+		}
+	}
+	sortPosts(sorted, m.sortMode)
+
+	items := make([]list.Item, len(sorted))
+	for i, p := range sorted {
+		items[i] = p
+	}
+	m.postList.SetItems(items)
+	m.postList.Title = "Blog Posts · " + m.sortMode.String()
+	if m.tagFilter != "" {
+		m.postList.Title += " · " + m.tagFilter
+	}
+}
+
+// setStatus shows a transient message in the footer and schedules it to clear.
+func (m *model) setStatus(text string) tea.Cmd {
+	m.statusMessage = text
+	m.statusID++
+	id := m.statusID
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearStatusMsg{id: id}
+	})
+}
+
+// setViewportContent fills the viewport with the selected post, either rendered
+// through glamour or as the raw markdown source. It is the single place posts are
+// rendered, and always wraps to the viewport's current width, so whichever of
+// WindowSizeMsg and postsLoadedMsg arrives last produces the final layout.
+func (m *model) setViewportContent() {
+	if m.selectedPost == nil || m.viewport.Width <= 0 {
+		return
+	}
+	if m.loadingBody {
+		m.footnoteURLs, m.collapsedBlocks, m.hiddenSpoilers = nil, nil, nil
+		m.setContent("Loading post...")
+		return
+	}
+	width := m.contentWidth()
+	if m.showRaw {
+		// Wrap rather than truncate long source lines so nothing is hidden
+		rawStyle := lipgloss.NewStyle()
+		if !m.noWrap {
+			rawStyle = rawStyle.Width(width)
+		}
+		_, m.footnoteURLs = m.opts.links.apply(stripTags(m.selectedPost.Content))
+		m.setContent(rawStyle.Render(m.selectedPost.Content))
+		return
+	}
+
+	markdown := m.selectedPost.Content
+	if m.opts.math {
+		// Before stripTags, which could take a < in LaTeX for a component tag
+		markdown = renderMath(markdown)
+	}
+	revealed := m.revealedSpoilers[m.selectedPost.key()]
+	markdown, hidden := renderContainers(markdown, revealed)
+	m.hiddenSpoilers = hidden
+	expanded := m.expandedBlocks[m.selectedPost.key()]
+	markdown, collapsed := collapseCodeBlocks(markdown, m.opts.collapseCode, expanded)
+	m.collapsedBlocks = collapsed
+
+	// Without wrapping, tables and code keep their natural width and the
+	// viewport scrolls sideways over them
+	wrap := width - 2
+	if m.noWrap {
+		wrap = 0
+	}
+
+	// glamour is slow on long posts, so reuse output for the same post, style and
+	// wrap. Blocks and spoilers are only ever opened, so counts identify the state.
+	shown := m.shownFootnotes[m.selectedPost.key()]
+	cacheKey := fmt.Sprintf("%s|%s|%d|%d|%d|%t", m.selectedPost.key(), m.glamourStyle(), wrap, len(expanded), len(revealed), shown)
+	postContent := stripTags(boldDefinitionTerms(markdown))
+	postContent, m.footnoteURLs = m.opts.links.apply(postContent)
+	if m.opts.links == linksFootnotes {
+		postContent += footnotesSection(m.footnoteURLs, shown)
+	}
+	formattedContent, ok := m.renderCache[cacheKey]
+	if ok {
+		m.stream = nil
+	} else if m.stream != nil && m.stream.key == cacheKey {
+		formattedContent = m.stream.view()
+	} else {
+		var err error
+		m.stream = nil
+		if len(postContent) > streamThreshold {
+			formattedContent, err = m.startStream(cacheKey, m.glamourStyle(), wrap, postContent)
+		} else {
+			formattedContent, err = renderMarkdown(m.glamourStyle(), wrap, postContent)
+		}
+		if err != nil {
+			log.Printf("Error rendering markdown: %v", err)
+			m.viewport.SetContent("Error rendering content.")
+			return
+		}
+		if m.stream == nil {
+			m.renderCache[cacheKey] = formattedContent
+		}
+	}
+
+	if m.readingSize() == readingNarrow {
+		formattedContent = spaceParagraphs(formattedContent)
+	}
+	if m.selectedPost.rightToLeft() {
+		formattedContent = alignRight(formattedContent)
+	}
+	if !m.hideMetadata {
+		formattedContent = m.metadataBlock(*m.selectedPost, width) + formattedContent
+	}
+	m.setContent(formattedContent)
+}
+
+// hscrollStep is how many columns ←/→ move an unwrapped post.
+const hscrollStep = 8
+
+// lineNumberGutter is the width taken by line numbers when they're shown.
+const lineNumberGutter = 6
+
+// availableWidth is the viewport width left over after line numbers.
+func (m model) availableWidth() int {
+	if m.lineNumbers {
+		return m.viewport.Width - lineNumberGutter
+	}
+	return m.viewport.Width
+}
+
+// contentWidth is the width posts are wrapped to, capped by the reading size.
+func (m model) contentWidth() int {
+	width := m.availableWidth()
+	if maxWidth := m.readingSize().maxWidth(); maxWidth > 0 {
+		width = min(width, maxWidth)
+	}
+	return width
+}
+
+// readingSize is the active reading size. Unknown saved values fall back to full width.
+func (m model) readingSize() readingSize {
+	if m.prefs.ReadingSize < 0 || m.prefs.ReadingSize >= numReadingSizes {
+		return readingFull
+	}
+	return m.prefs.ReadingSize
+}
+
+// setContent puts rendered content in the viewport, centering a reading column
+// narrower than the viewport and adding line numbers when enabled.
+func (m *model) setContent(content string) {
+	content = indentLines(content, (m.availableWidth()-m.contentWidth())/2)
+	if m.lineNumbers {
+		content = numberLines(content)
+	}
+	m.renderedContent = content
+	m.viewport.SetContent(content)
+	if m.follow {
+		m.viewport.GotoBottom()
+	}
+}
+
+// expandCodeBlock expands the first collapsed code block whose marker is in or
+// below the visible part of the viewport, or the last one above it.
+func (m *model) expandCodeBlock() {
+	m.openMarker(collapsedMarkerHint, m.collapsedBlocks, m.expandedBlocks)
+}
+
+// revealSpoiler reveals the next hidden spoiler, picked like expandCodeBlock.
+func (m *model) revealSpoiler() {
+	m.openMarker(spoilerMarkerHint, m.hiddenSpoilers, m.revealedSpoilers)
+}
+
+// openMarker finds the first rendered line containing hint at or below the top
+// of the viewport (or the last one above it) and records the matching entry of
+// indexes as opened for the selected post. Markers appear in the same order as
+// indexes.
+func (m *model) openMarker(hint string, indexes []int, opened map[string]map[int]bool) {
+	if m.selectedPost == nil || m.showRaw || len(indexes) == 0 {
+		return
+	}
+	target := len(indexes) - 1
+	marker := 0
+	for row, line := range strings.Split(m.renderedContent, "\n") {
+		if !strings.Contains(ansi.Strip(line), hint) {
+			continue
+		}
+		if row >= m.viewport.YOffset {
+			target = min(marker, target)
+			break
+		}
+		marker++
+	}
+
+	postKey := m.selectedPost.key()
+	if opened[postKey] == nil {
+		opened[postKey] = make(map[int]bool)
+	}
+	opened[postKey][indexes[target]] = true
+	offset := m.viewport.YOffset
+	m.setViewportContent()
+	if !m.follow {
+		m.viewport.SetYOffset(offset)
+	}
+}
+
+// numberLines prefixes every line with a dimmed, right-aligned line number.
+// Numbering happens after wrapping so each number matches one viewport row.
+func numberLines(content string) string {
+	numberStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = numberStyle.Render(fmt.Sprintf("%*d ", lineNumberGutter-2, i+1)) + " " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// glamourStyle is the active glamour style name, or the --style file. Unknown
+// saved names fall back to auto.
+func (m model) glamourStyle() string {
+	if m.opts.asciiOnly {
+		return styles.AsciiStyle
+	}
+	if m.opts.style != "" && m.prefs.GlamourStyle == m.opts.style {
+		return m.opts.style
+	}
+	if !slices.Contains(glamourStyles, m.prefs.GlamourStyle) {
+		return glamourStyles[0]
+	}
+	return m.prefs.GlamourStyle
+}
+
+// cycleGlamourStyle switches to the next built-in glamour style.
+func (m *model) cycleGlamourStyle() {
+	next := glamourStyles[0]
+	for i, style := range glamourStyles {
+		if style == m.glamourStyle() {
+			next = glamourStyles[(i+1)%len(glamourStyles)]
+			break
+		}
+	}
+	m.prefs.GlamourStyle = next
+}
+
+// metadataBlock renders the post's frontmatter as a styled block that sits above
+// the body inside the viewport, so it scrolls with the post.
+func (m model) metadataBlock(p PostMetadata, width int) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(p.accent())
+	metaStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+
+	var details []string
+	if !p.PublishDate.IsZero() {
+		details = append(details, m.opts.formatDate(p.PublishDate))
+	}
+	if p.UpdateDate.After(p.PublishDate) {
+		details = append(details, "updated "+m.opts.formatDate(p.UpdateDate))
+	}
+	if p.Author != "" {
+		details = append(details, "by "+p.Author)
+	}
+	if p.ReadingMinutes > 0 {
+		details = append(details, fmt.Sprintf("%d min read", p.ReadingMinutes))
+	}
+
+	lines := []string{titleStyle.Render(p.PostTitle)}
+	if len(details) > 0 {
+		lines = append(lines, metaStyle.Render(strings.Join(details, " · ")))
+	}
+	var taxonomy []string
+	if p.Category != "" {
+		taxonomy = append(taxonomy, "Category: "+p.Category)
+	}
+	if len(p.Tags) > 0 {
+		taxonomy = append(taxonomy, "Tags: "+strings.Join(p.Tags, ", "))
+	} else if m.opts.autoTags && len(p.SuggestedTags) > 0 {
+		taxonomy = append(taxonomy, "Suggested tags: "+strings.Join(p.SuggestedTags, ", "))
+	}
+	if len(taxonomy) > 0 {
+		lines = append(lines, metaStyle.Render(strings.Join(taxonomy, " · ")))
+	}
+
+	// Match glamour's left margin so the block lines up with the body
+	block := lipgloss.NewStyle().Width(width-2).Padding(1, 2, 0, 2).Render(strings.Join(lines, "\n"))
+	return block + "\n"
+}
+
+// homePost picks the post opened on entering the list, if any: the operator's
+// front page, then the configured home slug. Without either the list itself
+// is the first thing shown.
+func (m model) homePost() (PostMetadata, bool) {
+	if m.opts.homePage != nil {
+		return *m.opts.homePage, true
+	}
+	if m.opts.homeSlug != "" {
+		if p, ok := findPostBySlug(m.posts, m.opts.homeSlug); ok {
+			return p, true
+		}
+		log.Printf("Home slug %q not found, showing the list", m.opts.homeSlug)
+	}
+	return PostMetadata{}, false
+}
+
+// findPostBySlug returns the post with the given slug, or failing that the post
+// listing it among its aliases. Canonical slugs win, so a post can't be hidden
+// by another's stale alias.
+func findPostBySlug(posts []PostMetadata, slug string) (PostMetadata, bool) {
+	for _, p := range posts {
+		if p.Slug == slug {
+			return p, true
+		}
+	}
+	for _, p := range posts {
+		if slices.Contains(p.Aliases, slug) {
+			return p, true
+		}
+	}
+	return PostMetadata{}, false
+}
+
+// selectPost shows p in the viewport from the top and refreshes its related
+// posts. In metadata-only mode it returns the command fetching p's body.
+func (m *model) selectPost(p PostMetadata) tea.Cmd {
+	m.selectedPost = &p
+	m.related = m.relatedIdx.related(m.posts, p)
+	m.relatedCursor = -1
+	m.loadingBody = p.Content == "" && p.SourceURL != ""
+	m.setViewportContent()
+	if !m.follow {
+		m.viewport.GotoTop()
+	}
+	if m.loadingBody {
+		return fetchBodyCmd(p, newFetchClient(m.opts))
+	}
+	return nil
+}
+
+// showPost opens p on the post detail screen with the list cursor on it, so
+// going back lands where the reader would expect.
+func (m *model) showPost(p PostMetadata) tea.Cmd {
+	for i, item := range m.postList.VisibleItems() {
+		if item.(PostMetadata).key() == p.key() {
+			m.postList.Select(i)
+			break
+		}
+	}
+	m.currentScreen = postDetailScreen
+	return m.openPost(p)
+}
+
+// rerenderViewport re-renders the selected post, keeping the reader's relative
+// scroll position so toggles and resizes don't lose their place.
+func (m *model) rerenderViewport() {
+	scrollPercent := 0.0
+	if m.viewport.YOffset > 0 {
+		scrollPercent = m.viewport.ScrollPercent()
+	}
+	m.setViewportContent()
+	if maxOffset := m.viewport.TotalLineCount() - m.viewport.Height; maxOffset > 0 && !m.follow {
+		m.viewport.SetYOffset(int(scrollPercent * float64(maxOffset)))
+	}
+}
+
+// Helper views for header/footer of postDetailScreen
+func (m model) headerView() string {
 	if m.selectedPost == nil {
 		return ""
 	}
-	postTitleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Padding(0,1)
-	return postTitleStyle.Render(m.selectedPost.PostTitle)
+	postTitleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.selectedPost.accent()).Padding(0, 1)
+	header := postTitleStyle.Render(m.selectedPost.PostTitle)
+	// The badge stays in view here however far the post is scrolled
+	if badge := freshnessBadge(*m.selectedPost, time.Now(), m.opts.freshness); badge != "" {
+		header += badge
+	}
+	return header
+}
+
+// relatedView renders the one-line panel of related posts shown under the post.
+func (m model) relatedView() string {
+	if len(m.related) == 0 {
+		return ""
+	}
+	dimmed := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+	selected := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+
+	titles := make([]string, len(m.related))
+	for i, idx := range m.related {
+		if i == m.relatedCursor {
+			titles[i] = selected.Render("▸ " + m.posts[idx].Title())
+		} else {
+			titles[i] = dimmed.Render(m.posts[idx].Title())
+		}
+	}
+	line := "Related: " + strings.Join(titles, dimmed.Render(" │ "))
+	return lipgloss.NewStyle().Padding(0, 1).MaxWidth(m.width).Render(line)
 }
 
 func (m model) footerView() string {
-	return lipgloss.NewStyle().Padding(0,1).Render("[↑/k up, ↓/j down, q/esc/b back]")
+	viewMode := "rendered"
+	if m.showRaw {
+		viewMode = "raw"
+	}
+	// The full list of keys lives in the ? help overlay
+	footer := fmt.Sprintf("[↑/k up, ↓/j down, m raw/rendered, ? help, q back] %s · %s", viewMode, m.glamourStyle())
+	if m.follow {
+		footer += " · following"
+	}
+	if m.noWrap {
+		footer += " · no wrap"
+	}
+	if m.reporting {
+		footer = "Report reason: " + m.reportInput.View() + "  [enter send, esc cancel]"
+	} else if m.statusMessage != "" {
+		footer += " · " + m.statusMessage
+	}
+
+	// How far through the post the reader is hugs the right edge, rounded
+	// down so 100% means the end is in view; the hints give way to it when narrow
+	progress := fmt.Sprintf("%d%%", int(m.viewport.ScrollPercent()*100))
+	room := m.width - 2 - lipgloss.Width(progress) - 1
+	if lipgloss.Width(footer) > room {
+		footer = ansi.Truncate(footer, max(0, room), "…")
+	}
+	gap := strings.Repeat(" ", max(1, room+1-lipgloss.Width(footer)))
+	return lipgloss.NewStyle().Padding(0, 1).Render(lipgloss.JoinHorizontal(lipgloss.Top, footer, gap, progress))
 }
 
 func (m model) View() string {
+	if m.opts.asciiOnly {
+		return toASCII(m.view())
+	}
+	return m.view()
+}
+
+func (m model) view() string {
 	if !m.ready { // Don't render until viewport is initialized
 		return "Initializing..."
 	}
@@ -408,67 +1973,170 @@ func (m model) View() string {
 		Background(adaptiveBackground).
 		Foreground(adaptiveForeground)
 
+	if maintenanceMode.Load() {
+		return baseStyle.Width(m.width).Height(m.height).Align(lipgloss.Center, lipgloss.Center).
+			Render(maintenanceNotice + "\n\n(Press 'q' to quit)")
+	}
+
+	// Inline (no alt-screen) rendering shouldn't pad centered screens to the full terminal height
+	fillHeight := m.height
+	if m.opts.noAltScreen {
+		fillHeight = 0
+	}
+
+	if m.showHelp {
+		return baseStyle.Width(m.width).Height(fillHeight).Align(lipgloss.Center, lipgloss.Center).
+			Render(helpView(m.keys, m.currentScreen, m.opts.asciiOnly))
+	}
+
 	switch m.currentScreen {
+	case archiveScreen:
+		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+		footer := "[↑/k ↓/j move, enter/→ open, ← fold, q back, ? help]"
+		return lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Padding(0, 1).Render(titleStyle.Render("Archive")),
+			lipgloss.NewStyle().Padding(0, 1).Height(m.height-2).Render(m.archive.view(m.width-2, m.height-2, m.opts.formatDate)),
+			lipgloss.NewStyle().Padding(0, 1).Render(footer),
+		)
+
+	case firstRunScreen:
+		return baseStyle.Width(m.width).Height(fillHeight).Align(lipgloss.Center, lipgloss.Center).
+			Render(firstRunView(m))
+
 	case splashScreen:
-		splashContainerStyle := baseStyle.Width(m.width).Height(m.height).Align(lipgloss.Center, lipgloss.Center)
+		splashContainerStyle := baseStyle.Width(m.width).Height(fillHeight).Align(lipgloss.Center, lipgloss.Center)
 		mainMessageStyle := lipgloss.NewStyle().Foreground(adaptiveForeground)
-		mainMessageContent := mainMessageStyle.Render(m.splashMessage)
+		motd, splashMessage := strings.TrimRight(m.opts.motd, "\n"), m.splashMessage
+		if m.typing() {
+			var left int
+			motd, left = typeOut(motd, m.typed)
+			splashMessage, _ = typeOut(splashMessage, left)
+		}
+		mainMessageContent := mainMessageStyle.Render(splashMessage)
 		flashingMessageContent := ""
-		if m.showFlashMessage {
+		if m.showFlashMessage && !m.typing() {
 			flashStyle := lipgloss.NewStyle().Foreground(adaptiveForeground)
 			flashingMessageContent = flashStyle.Render(m.flashMessage)
 		}
-		combinedContent := lipgloss.JoinVertical(lipgloss.Center,
-			mainMessageContent,
-			"",
-			flashingMessageContent,
-		)
+		var parts []string
+		if motd != "" {
+			parts = append(parts, motd, "")
+		}
+		parts = append(parts, mainMessageContent)
+		if anim := m.opts.splashAnim.render(m.splashFrame, m.width); anim != "" {
+			parts = append(parts, "", anim)
+		}
+		parts = append(parts, "", flashingMessageContent)
+		combinedContent := lipgloss.JoinVertical(lipgloss.Center, parts...)
 		return splashContainerStyle.Render(combinedContent)
 
-	case listScreen:
+	case statsScreen:
+		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+		footer := "[q back, ? help]"
+		return lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Padding(0, 1).Render(titleStyle.Render("Your reading stats")),
+			lipgloss.NewStyle().Padding(1, 1, 0, 1).Height(m.height-2).Render(m.stats.summary(time.Now()).view()),
+			lipgloss.NewStyle().Padding(0, 1).Render(footer),
+		)
+
+	case listScreen, todayScreen:
 		if m.loadingPosts {
-			loadingStyle := baseStyle.Width(m.width).Height(m.height).Align(lipgloss.Center, lipgloss.Center)
-			return loadingStyle.Render("Loading latest post...")
+			var status []string
+			if m.fetchProgress.total > 0 {
+				status = append(status, fmt.Sprintf("%d of %d posts", m.fetchProgress.loaded, m.fetchProgress.total))
+			}
+			if m.fetchProgress.attempt > 0 {
+				status = append(status, fmt.Sprintf("Retrying (attempt %d/%d)…", m.fetchProgress.attempt, m.fetchProgress.attempts))
+			}
+			if len(status) == 0 {
+				skeleton := renderSkeleton(m.width, m.height, m.skeletonFrame, !m.opts.reduceMotion)
+				return baseStyle.Width(m.width).Height(fillHeight).Render(skeleton)
+			}
+			// Keep the last line for how far the fetch has got
+			skeleton := renderSkeleton(m.width, m.height-1, m.skeletonFrame, !m.opts.reduceMotion)
+			counter := lipgloss.NewStyle().Padding(0, 2).Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}).
+				Render(strings.Join(status, " · "))
+			return baseStyle.Width(m.width).Height(fillHeight).Render(
+				lipgloss.PlaceVertical(max(0, m.height-1), lipgloss.Top, skeleton) + "\n" + counter)
 		}
 		if m.postsError != nil {
-			errorStyle := baseStyle.Width(m.width).Height(m.height).Align(lipgloss.Center, lipgloss.Center)
-			content := fmt.Sprintf("Error loading post: %v\n\n(Press 'q' to quit)", m.postsError)
+			errorStyle := baseStyle.Width(m.width).Height(fillHeight).Align(lipgloss.Center, lipgloss.Center)
+			content := fmt.Sprintf("Error loading post: %v\n\n(Press 'q' to go back, 'Q' to quit)", m.postsError)
 			return errorStyle.Render(content)
 		}
-		if len(m.postList.Items()) > 0 {
-			// Don't reset viewport content here - it was set when posts were loaded
+		if m.currentScreen == todayScreen {
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+			title := fmt.Sprintf("On this day · %s %d", m.today.date.Month(), m.today.date.Day())
+			footer := "[↑/k ↓/j move, enter open, q back, ? help]"
 			return lipgloss.JoinVertical(lipgloss.Left,
-				m.viewport.View(),
-				lipgloss.NewStyle().Padding(0, 1).Render("[↑/k up, ↓/j down, q/esc quit]"),
+				lipgloss.NewStyle().Padding(0, 1).Render(titleStyle.Render(title)),
+				lipgloss.NewStyle().Padding(1, 1, 0, 1).Height(m.height-2).Render(m.today.view(m.width-2)),
+				lipgloss.NewStyle().Padding(0, 1).Render(footer),
 			)
 		}
-		return baseStyle.Width(m.width).Height(m.height).Align(lipgloss.Center, lipgloss.Center).Render("No posts available.")
+		if len(m.postList.Items()) == 0 {
+			return baseStyle.Width(m.width).Height(fillHeight).Align(lipgloss.Center, lipgloss.Center).Render("No posts available.")
+		}
+		footer := ""
+		if m.jumping {
+			footer = "Jump to date: " + m.jumpInput.View() + "  [enter jump, esc cancel]"
+		} else if m.statusMessage != "" {
+			footer = m.statusMessage
+		}
+		if footer == "" {
+			return m.postList.View()
+		}
+		// Make room for the prompt or status under the list
+		postList := m.postList
+		postList.SetHeight(m.height - 1)
+		return lipgloss.JoinVertical(lipgloss.Left, postList.View(), lipgloss.NewStyle().Padding(0, 1).Render(footer))
 
+	case tagsScreen:
+		if len(m.tagList.Items()) == 0 {
+			return baseStyle.Width(m.width).Height(fillHeight).Align(lipgloss.Center, lipgloss.Center).Render("No tagged posts.\n\n(Press 'q' to go back)")
+		}
+		return m.tagList.View()
+
+	case postDetailScreen:
+		sections := []string{m.headerView(), m.viewport.View()}
+		if related := m.relatedView(); related != "" {
+			sections = append(sections, related)
+		}
+		sections = append(sections, m.footerView())
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
 
 	default:
-		unknownScreenStyle := baseStyle.Width(m.width).Height(m.height).Align(lipgloss.Center, lipgloss.Center)
+		unknownScreenStyle := baseStyle.Width(m.width).Height(fillHeight).Align(lipgloss.Center, lipgloss.Center)
 		return unknownScreenStyle.Render("Unknown screen")
 	}
 }
 
-// transformLinksToFootnotes takes a markdown string and converts inline links to footnotes.
-// It returns the modified markdown and a list of URLs for the footnotes.
-func transformLinksToFootnotes(markdownContent string) string {
-	// Regex for [text](url) using a raw string literal for clarity and correctness.
-	// Group 1: text
-	// Group 2: url
-	re := regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`) // Use raw string literal
+// markdownLinkRe matches [text](url) and ![alt](url). Text can't hold brackets,
+// so an image inside a link, as badges are, matches as the image.
+// Group 1: "!" for an image
+// Group 2: text
+// Group 3: url
+var markdownLinkRe = regexp.MustCompile(`(!?)\[([^\[\]]*)\]\(([^)]+)\)`)
 
+// transformLinksToFootnotes converts the inline links in markdown to numbered
+// footnotes, as linkMode.apply does for --links footnotes, the default. It
+// returns the modified markdown and the footnote URLs, in order. Links to the
+// same URL share one footnote, numbered where it first appears.
+func transformLinksToFootnotes(markdownContent string) (string, []string) {
 	var footnotes []string
-	footnoteIndex := 1
+	footnoteIndex := make(map[string]int) // URL to its footnote number
 
-	transformedContent := re.ReplaceAllStringFunc(markdownContent, func(match string) string {
-		submatches := re.FindStringSubmatch(match)
-		if len(submatches) < 3 {
-			return match 
+	transformedContent := markdownLinkRe.ReplaceAllStringFunc(markdownContent, func(match string) string {
+		submatches := markdownLinkRe.FindStringSubmatch(match)
+		if len(submatches) < 4 {
+			return match
+		}
+		isImage := submatches[1] == "!"
+		linkText := submatches[2]
+		url := submatches[3]
+		if linkText == "" && !isImage {
+			return match
 		}
-		linkText := submatches[1]
-		url := submatches[2]
 
 		// Basic check to avoid re-processing if it looks like a footnote marker already
 		// e.g., if linkText is "[123]"
@@ -477,66 +2145,217 @@ func transformLinksToFootnotes(markdownContent string) string {
 				return match // It's already a footnote reference like "[1]", skip.
 			}
 		}
-		
+
 		// Avoid re-processing if the URL part is already a footnote definition (common in some markdown outputs)
 		if strings.HasPrefix(url, "#fn:") || strings.HasPrefix(url, "#fnref:") {
-		    return match
+			return match
 		}
 
+		// Images can't be drawn in the viewport, so show the alt text with the URL as a footnote
+		if isImage {
+			fields := strings.Fields(url)
+			if len(fields) == 0 {
+				return match // No URL to footnote, as in ![alt]( )
+			}
+			url = fields[0] // drop an optional "title"
+			linkText = `\[image\]`
+			if alt := strings.TrimSpace(submatches[2]); alt != "" {
+				linkText = fmt.Sprintf(`\[image: %s\]`, alt)
+			}
+		}
 
-		footnotes = append(footnotes, url)
-		newLink := fmt.Sprintf("%s [%d]", linkText, footnoteIndex)
-		footnoteIndex++
-		return newLink
+		index, ok := footnoteIndex[url]
+		if !ok {
+			footnotes = append(footnotes, url)
+			index = len(footnotes)
+			footnoteIndex[url] = index
+		}
+		return fmt.Sprintf("%s [%d]", linkText, index)
 	})
 
-	if len(footnotes) > 0 {
-		var footnotesSection strings.Builder
-		footnotesSection.WriteString("\n\n---\n**Footnotes:**\n")
-		for i, url := range footnotes {
-			footnotesSection.WriteString(fmt.Sprintf("[%d]: %s\n", i+1, url))
+	return transformedContent, footnotes
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "validate" {
+		os.Exit(runValidate(args[1:], os.Stdout))
+	}
+	sshMode := len(args) > 0 && args[0] == "ssh"
+	warmMode := len(args) > 0 && args[0] == "warm"
+	if sshMode || warmMode {
+		args = args[1:]
+	}
+
+	opts := options{repo: defaultRepoConfig()}
+	flag.BoolVar(&opts.noAltScreen, "no-altscreen", false, "render inline instead of using the terminal's alternate screen")
+	flag.StringVar(&opts.reportWebhook, "report-webhook", "", "URL to POST post reports to; reporting is disabled when empty")
+	flag.StringVar(&opts.highlight, "highlight", "", "selected list item color (hex like #FF79C6 or ANSI 0-255)")
+	flag.StringVar(&opts.highlightBg, "highlight-bg", "", "selected list item background color (hex or ANSI 0-255)")
+	flag.StringVar(&opts.repo.owner, "owner", defaultRepoOwner, "GitHub user or organization owning the blog repo")
+	flag.StringVar(&opts.repo.name, "repo", defaultRepoName, "GitHub repo to load posts from")
+	flag.StringVar(&opts.repo.path, "path", defaultRepoPath, "directory of the repo holding the .mdx posts")
+	flag.StringVar(&opts.gistID, "gist", "", "load posts from the .md/.mdx files of this GitHub Gist ID")
+	flag.BoolVar(&opts.reduceMotion, "reduce-motion", false, "disable decorative animation such as the loading shimmer")
+	flag.IntVar(&opts.typewriter, "typewriter", 0, "type the splash and MOTD out at this many characters per second, modem style (0 disables)")
+	splashAnimation := flag.String("splash-anim", "none", "animation under the splash message: none, marquee or stars")
+	flag.StringVar(&opts.homeSlug, "home-slug", "", "slug of a post to open on entering the list (default: show the list)")
+	homeFile := flag.String("home-file", "", "markdown file rendered as the front page on entering the list")
+	locale := flag.String("locale", "", "locale for dates, e.g. en_GB, fr or ja (default: LC_ALL, LC_TIME or LANG; ISO dates when unknown)")
+	flag.BoolVar(&opts.noStatusBar, "no-statusbar", false, "hide the list's status bar to save a line (toggle with B)")
+	flag.BoolVar(&opts.math, "math", false, "render $...$ and $$...$$ LaTeX math as Unicode in code spans and blocks")
+	flag.BoolVar(&opts.noFirstRun, "no-first-run", false, "skip the one-time appearance prompt shown when no settings are saved")
+	flag.BoolVar(&opts.numberedList, "numbered-list", false, "prefix list items with their position (toggle with #)")
+	flag.StringVar(&opts.goodbye, "goodbye", "", "farewell message printed on exit, e.g. \"Thanks for visiting — 73!\"")
+	flag.BoolVar(&opts.metadataOnly, "metadata-only", false, "keep only post metadata in memory and fetch each post's body when it's opened")
+	flag.BoolVar(&opts.randomUnread, "random-unread", false, "make r likelier to pick posts you haven't read")
+	flag.BoolVar(&opts.incremental, "incremental", false, "fetch only the posts changed since the cached commit, using GitHub's compare API")
+	flag.BoolVar(&opts.refresh, "refresh", false, "don't fall back to the cached posts of the last successful fetch when fetching fails")
+	flag.BoolVar(&opts.showPrivate, "show-private", false, "list posts marked private: true in their frontmatter")
+	links := flag.String("links", "footnotes", "how links in posts are shown: footnotes (numbered, URLs listed at the end), inline (as glamour renders them) or hidden (just the text)")
+	noFootnotes := flag.Bool("no-footnotes", false, "same as --links inline")
+	flag.StringVar(&opts.style, "style", "", "glamour style to start with (auto, dark, light, dracula, tokyo-night, pink, ascii, notty) or a JSON style file")
+	flag.BoolVar(&opts.asciiOnly, "ascii", false, "draw only ASCII characters, for terminals that can't show box drawing or emoji")
+	flag.BoolVar(&opts.autoTags, "auto-tags", false, "suggest tags from the code languages and recurring names in posts that have none")
+	flag.IntVar(&opts.collapseCode, "collapse-code", 0, "collapse fenced code blocks longer than this many lines (0 disables)")
+	flag.StringVar(&opts.description, "description", defaultDescriptionTemplate, "text/template for the line under each list item; fields: .date .category .tags .author .readingTime")
+	freshDays := flag.Int("fresh-days", 30, "posts updated within this many days are badged fresh")
+	recentDays := flag.Int("recent-days", 365, "posts updated within this many days are badged recent; older ones are archived")
+	flag.IntVar(&opts.scrollLines, "scroll-lines", 1, "lines to scroll a post per up/down keypress")
+	flag.IntVar(&opts.retries, "retries", defaultRetries, "times to retry a fetch after a network error, 5xx or 429, waiting 200ms, then twice as long each time")
+	motdFile := flag.String("motd-file", "", "message of the day (text or ANSI art) shown above the splash in SSH sessions")
+	sshAddr := flag.String("ssh-addr", defaultSSHAddr(), "address the SSH server listens on, as host:port (default :$PORT, or :23234)")
+	hostKey := flag.String("host-key", "ssh_host_ed25519", "path of the SSH server's ed25519 host key, generated if missing; other --host-keys types are kept beside it")
+	hostKeys := flag.String("host-keys", defaultHostKeyTypes, "SSH host key types to serve, from ed25519, ecdsa and rsa; missing keys are generated")
+	kex := flag.String("kex", defaultKeyExchanges, "SSH key exchange algorithms to allow, in preference order")
+	ciphers := flag.String("ciphers", defaultCiphers, "SSH ciphers to allow, in preference order")
+	macs := flag.String("macs", defaultMACs, "SSH MACs to allow, in preference order")
+	minTLS := flag.String("min-tls", "1.2", "minimum TLS version for outgoing requests: 1.0, 1.1, 1.2 or 1.3")
+	warmInterval := flag.Duration("interval", 10*time.Minute, "how often bbs warm refreshes the post cache")
+	flag.CommandLine.Parse(args)
+
+	var err error
+	if opts.minTLS, err = parseTLSVersion(*minTLS); err != nil {
+		log.Fatalf("invalid --min-tls: %v", err)
+	}
+
+	if opts.links, err = parseLinkMode(*links); err != nil {
+		log.Fatalf("invalid --links: %v", err)
+	}
+	if *noFootnotes {
+		opts.links = linksInline
+	}
+
+	if opts.splashAnim, err = parseSplashAnim(*splashAnimation); err != nil {
+		log.Fatalf("invalid --splash-anim: %v", err)
+	}
+	if opts.reduceMotion {
+		opts.splashAnim = splashAnimNone
+		opts.typewriter = 0
+	}
+	if opts.asciiOnly {
+		opts.goodbye = toASCII(opts.goodbye)
+	}
+	if opts.style != "" && !slices.Contains(glamourStyles, opts.style) {
+		if _, err := glamour.NewTermRenderer(glamour.WithStylePath(opts.style)); err != nil {
+			log.Fatalf("invalid --style: %v", err)
 		}
-		transformedContent += footnotesSection.String()
 	}
 
-	return transformedContent
-}
+	if *freshDays < 1 || *recentDays < *freshDays {
+		log.Fatalf("invalid --fresh-days %d / --recent-days %d: need 1 <= fresh <= recent", *freshDays, *recentDays)
+	}
+	opts.freshness = freshnessAges{fresh: time.Duration(*freshDays) * 24 * time.Hour, recent: time.Duration(*recentDays) * 24 * time.Hour}
 
-// Update the stripTags function to remove specific import statements.
-func stripTags(content string) string {
-	re := regexp.MustCompile(`<[^>]+>|{[^}]+}|import CallToAction from '~\/components\/widgets\/CallToAction\.astro';`)
-	return re.ReplaceAllString(content, "")
-}
+	if opts.scrollLines < 1 {
+		log.Fatalf("invalid --scroll-lines %d: must be at least 1", opts.scrollLines)
+	}
+	if opts.retries < 0 {
+		log.Fatalf("invalid --retries %d: must be at least 0", opts.retries)
+	}
+
+	formatDate, used, ok := localDateFormat(*locale)
+	if !ok {
+		log.Printf("Unknown locale %q, using ISO dates", used)
+	}
+	opts.formatDate = formatDate
+
+	if _, err := parseDescriptionTemplate(opts.description); err != nil {
+		log.Fatalf("invalid --description template: %v", err)
+	}
+
+	if *homeFile != "" {
+		home, err := loadHomePage(*homeFile)
+		if err != nil {
+			log.Fatalf("could not load --home-file: %v", err)
+		}
+		opts.homePage = &home
+	}
+
+	if opts.highlight != "" && !isValidColor(opts.highlight) {
+		log.Fatalf("invalid --highlight color %q", opts.highlight)
+	}
+	if opts.highlightBg != "" && !isValidColor(opts.highlightBg) {
+		log.Fatalf("invalid --highlight-bg color %q", opts.highlightBg)
+	}
+
+	// Keep the post cache fresh without a UI until told to stop
+	if warmMode {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := runWarm(ctx, opts, *warmInterval); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-func main() {
 	// If running as an SSH app, start the SSH server
-	if len(os.Args) > 1 && os.Args[1] == "ssh" {
-		pemBytes, err := os.ReadFile("ssh_host_ed25519")
+	if sshMode {
+		opts.sshMode = true
+		watchMaintenanceSignal()
+
+		serverOpts, err := hostKeyOptions(*hostKeys, *hostKey)
 		if err != nil {
-			log.Fatalf("could not read SSH key PEM file: %v", err)
-		}
-		
-		// Get port from environment variable or use default
-		port := os.Getenv("PORT")
-		if port == "" {
-			port = "23234"
-		}
-		address := ":" + port
-		
-		server, err := wish.NewServer(
-			wish.WithAddress(address),
-			wish.WithHostKeyPEM(pemBytes),
+			log.Fatalf("invalid --host-keys: %v", err)
+		}
+		algorithms, err := algorithmsOption(*kex, *ciphers, *macs)
+		if err != nil {
+			log.Fatalf("invalid SSH algorithms: %v", err)
+		}
+		serverOpts = append(append(serverOpts, algorithms), authOptions()...)
+		if *motdFile != "" {
+			if opts.motd, err = loadMOTD(*motdFile); err != nil {
+				log.Fatalf("could not load --motd-file: %v", err)
+			}
+		}
+		if _, _, err := net.SplitHostPort(*sshAddr); err != nil {
+			log.Fatalf("invalid --ssh-addr %q: %v", *sshAddr, err)
+		}
+
+		server, err := wish.NewServer(append(serverOpts,
+			wish.WithAddress(*sshAddr),
 			wish.WithMiddleware(
 				bubbletea.Middleware(func(sess ssh.Session) (tea.Model, []tea.ProgramOption) {
-					return initialModel(), nil
+					m := initialModel(opts, settings{})
+					m.clipboard = sess // The server's clipboard is no use to the reader
+					m.loadStats(sessionStatsUser(sess))
+					return m, nil
 				}),
+				goodbyeMiddleware(opts.goodbye),
+				maintenanceMiddleware(), // Runs first: turns away new sessions during maintenance
 			),
-		)
+		)...)
 		if err != nil {
 			log.Fatalf("could not start SSH server: %v", err)
 		}
-		log.Printf("SSH TUI server started on port %s. Connect with: ssh -p %s <user>@<host>", port, port)
-		if err := server.ListenAndServe(); err != nil {
+		// Listening here rather than in ListenAndServe logs the port :0 picked
+		ln, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			log.Fatalf("could not listen on %s: %v", server.Addr, err)
+		}
+		_, port, _ := net.SplitHostPort(ln.Addr().String())
+		log.Printf("SSH TUI server listening on %s. Connect with: ssh -p %s <user>@<host>", ln.Addr(), port)
+		if err := server.Serve(ln); err != nil {
 			log.Fatalf("SSH server error: %v", err)
 		}
 		return
@@ -549,8 +2368,19 @@ func main() {
 	}
 	defer f.Close()
 
-	p := tea.NewProgram(initialModel())
+	// Alt-screen makes no sense when stdout isn't a terminal (piping, CI, captures)
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		opts.noAltScreen = true
+	}
+
+	m := initialModel(opts, loadSettings())
+	m.loadStats(localStatsUser)
+	p := tea.NewProgram(m)
 	if _, errP := p.Run(); errP != nil {
 		log.Fatalf("Error running program: %v", errP)
 	}
+	// Run has restored the terminal by now, so this lands on the normal screen
+	if opts.goodbye != "" {
+		fmt.Println(opts.goodbye)
+	}
 }
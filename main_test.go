@@ -0,0 +1,758 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// contentsServer serves a directory listing split into pages, each page
+// answered with the Link header link gives it.
+func contentsServer(t *testing.T, pages [][]GitHubContent, link func(srvURL string, page int) string) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 0
+		if p := r.URL.Query().Get("page"); p != "" {
+			n, _ := strconv.Atoi(p)
+			page = n - 1
+		}
+		if page < 0 || page >= len(pages) {
+			http.NotFound(w, r)
+			return
+		}
+		if l := link(srv.URL, page); l != "" {
+			w.Header().Set("Link", l)
+		}
+		json.NewEncoder(w).Encode(pages[page])
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchContents(t *testing.T) {
+	pages := [][]GitHubContent{
+		{{Name: "a.mdx", Path: "posts/a.mdx", Type: "file"}, {Name: "b.mdx", Path: "posts/b.mdx", Type: "file"}},
+		{{Name: "c.mdx", Path: "posts/c.mdx", Type: "file"}},
+	}
+	tests := []struct {
+		name  string
+		link  func(srvURL string, page int) string
+		paths []string
+	}{
+		{
+			name: "next link followed",
+			link: func(srvURL string, page int) string {
+				if page == 0 {
+					return "<" + srvURL + `/contents?page=2>; rel="next", <` + srvURL + `/contents?page=2>; rel="last"`
+				}
+				return "<" + srvURL + `/contents?page=1>; rel="first", <` + srvURL + `/contents?page=1>; rel="prev"`
+			},
+			paths: []string{"posts/a.mdx", "posts/b.mdx", "posts/c.mdx"},
+		},
+		{
+			name:  "no link header",
+			link:  func(string, int) string { return "" },
+			paths: []string{"posts/a.mdx", "posts/b.mdx"},
+		},
+		{
+			name:  "malformed link header",
+			link:  func(srvURL string, _ int) string { return srvURL + "/contents?page=2 next" },
+			paths: []string{"posts/a.mdx", "posts/b.mdx"},
+		},
+		{
+			name:  "no next rel",
+			link:  func(srvURL string, _ int) string { return "<" + srvURL + `/contents?page=2>; rel="last"` },
+			paths: []string{"posts/a.mdx", "posts/b.mdx"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := contentsServer(t, pages, tt.link)
+			contents, err := fetchContents(testClient(0), srv.URL+"/contents")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, c := range contents {
+				paths = append(paths, c.Path)
+			}
+			if !slices.Equal(paths, tt.paths) {
+				t.Errorf("got %q, want %q", paths, tt.paths)
+			}
+		})
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		link, want string
+	}{
+		{`<https://api.github.com/x?page=2>; rel="next", <https://api.github.com/x?page=5>; rel="last"`, "https://api.github.com/x?page=2"},
+		{`<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=3>; rel="next"`, "https://api.github.com/x?page=3"},
+		{`<https://api.github.com/x?page=1>; rel="first"`, ""},
+		{`https://api.github.com/x?page=2; rel=next`, ""},
+		{`garbage`, ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := nextPageURL(tt.link); got != tt.want {
+			t.Errorf("nextPageURL(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestFetchPostFiles(t *testing.T) {
+	const files, failing = 20, 5
+	var inFlight, most atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+		}
+		time.Sleep(20 * time.Millisecond) // Long enough for the pool to fill up
+		if r.URL.Path == fmt.Sprintf("/post-%d.mdx", failing) {
+			http.Error(w, "broken", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, "---\ntitle: %s\n---\nBody\n", strings.TrimPrefix(r.URL.Path, "/"))
+	}))
+	defer srv.Close()
+
+	var contents []GitHubContent
+	var want []string
+	for i := range files {
+		name := fmt.Sprintf("post-%d.mdx", i)
+		contents = append(contents, GitHubContent{Name: name, Path: "posts/" + name, Type: "file", DownloadURL: srv.URL + "/" + name})
+		if i != failing {
+			want = append(want, name)
+		}
+	}
+	contents = append(contents, GitHubContent{Name: "images", Path: "posts/images", Type: "dir"})
+
+	posts, err := fetchPostFiles(testClient(0), contents, nil)
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("post-%d.mdx", failing)) {
+		t.Errorf("got error %v, want post-%d.mdx's", err, failing)
+	}
+	var got []string
+	for _, p := range posts {
+		got = append(got, p.PostTitle)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got posts %q, want %q", got, want)
+	}
+	if m := most.Load(); m > fetchWorkers {
+		t.Errorf("%d downloads ran at once, want at most %d", m, fetchWorkers)
+	} else if m < 2 {
+		t.Errorf("only %d download ran at once, want them in parallel", m)
+	}
+}
+
+func TestTransformLinksToFootnotes(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+		urls           []string
+	}{
+		{
+			name: "repeated URL shares its number",
+			in:   "[Go](https://go.dev) and [Charm](https://charm.sh), then [Go again](https://go.dev)",
+			want: "Go [1] and Charm [2], then Go again [1]",
+			urls: []string{"https://go.dev", "https://charm.sh"},
+		},
+		{
+			name: "numbered where first seen",
+			in:   "[a](https://a.example) [b](https://b.example) [c](https://c.example) [b](https://b.example) [a](https://a.example) [d](https://d.example)",
+			want: "a [1] b [2] c [3] b [2] a [1] d [4]",
+			urls: []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"},
+		},
+		{
+			name: "image and link to the same URL",
+			in:   "![Logo](https://charm.sh/logo.png \"Charm\") [logo](https://charm.sh/logo.png)",
+			want: `\[image: Logo\] [1] logo [1]`,
+			urls: []string{"https://charm.sh/logo.png"},
+		},
+		{
+			name: "footnote references left alone",
+			in:   "[[1]](#fn:1) and [](https://empty.example)",
+			want: "[[1]](#fn:1) and [](https://empty.example)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, urls := transformLinksToFootnotes(tt.in)
+			if got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+			if !slices.Equal(urls, tt.urls) {
+				t.Errorf("got footnotes %q, want %q", urls, tt.urls)
+			}
+			if again, _ := transformLinksToFootnotes(tt.in); again != got {
+				t.Errorf("numbering changed between runs: %q then %q", got, again)
+			}
+		})
+	}
+}
+
+func TestSplitFrontmatterAt(t *testing.T) {
+	tests := []struct {
+		name, in    string
+		front, body string
+		format      frontmatterFormat
+		line        int
+		ok          bool
+	}{
+		{name: "YAML", in: "---\ntitle: A\n---\nBody\n", front: "title: A\n", body: "Body\n", line: 2, ok: true},
+		{name: "CRLF and trailing spaces", in: "---  \r\ntitle: A\r\n--- \r\nBody", front: "title: A\r\n", body: "Body", line: 2, ok: true},
+		{name: "byte order mark", in: "\ufeff---\ntitle: A\n---\n", front: "title: A\n", line: 2, ok: true},
+		{name: "after blank lines and comments", in: "\n<!-- one -->\n<!--\ntwo\n-->\n---\ntitle: A\n---\nBody", front: "title: A\n", body: "Body", line: 7, ok: true},
+		{name: "rule in the body", in: "---\ntitle: A\n---\nOne\n---\nTwo", front: "title: A\n", body: "One\n---\nTwo", line: 2, ok: true},
+		{name: "empty", in: "---\n---\nBody", body: "Body", line: 2, ok: true},
+		{name: "TOML", in: "+++\ntitle = 'A'\n+++\nBody", front: "title = 'A'\n", body: "Body", format: tomlFrontmatter, line: 2, ok: true},
+		{name: "JSON", in: "\n{\"title\": \"A\"}\nBody", front: `{"title": "A"}`, body: "Body", format: jsonFrontmatter, line: 2, ok: true},
+		{name: "MDX comment isn't JSON", in: "{/* draft */}\nBody"},
+		{name: "unclosed", in: "---\ntitle: A\nBody"},
+		{name: "mismatched fences", in: "---\ntitle: A\n+++\nBody"},
+		{name: "text first", in: "Intro\n---\ntitle: A\n---\n"},
+		{name: "fence with text", in: "--- yaml\ntitle: A\n---\n"},
+		{name: "nothing", in: "\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			front, body, format, line, ok := splitFrontmatterAt(tt.in)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+			if front != tt.front || body != tt.body || format != tt.format || line != tt.line {
+				t.Errorf("got %q, %q, %s at line %d; want %q, %q, %s at line %d", front, body, format, line, tt.front, tt.body, tt.format, tt.line)
+			}
+		})
+	}
+}
+
+func TestDedupeContents(t *testing.T) {
+	in := []GitHubContent{
+		{Name: "a.mdx", Path: "posts/a.mdx", DownloadURL: "first"},
+		{Name: "b.mdx", Path: "posts/b.mdx"},
+		{Name: "a.mdx", Path: "posts/a.mdx", DownloadURL: "second"},
+		{Name: "a.mdx", Path: "drafts/a.mdx"},
+	}
+	got := dedupeContents(in)
+	want := []GitHubContent{in[0], in[1], in[3]}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if in[2].DownloadURL != "second" {
+		t.Errorf("the input was changed: %+v", in)
+	}
+	if got := dedupeContents(nil); len(got) != 0 {
+		t.Errorf("got %+v from no contents", got)
+	}
+}
+
+func TestDecodeContents(t *testing.T) {
+	t.Run("directory", func(t *testing.T) {
+		got, err := decodeContents([]byte(`[{"name": "a.mdx", "path": "posts/a.mdx", "type": "file", "download_url": "https://raw/a.mdx"}, {"name": "img", "path": "posts/img", "type": "dir"}]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []GitHubContent{
+			{Name: "a.mdx", Path: "posts/a.mdx", Type: "file", DownloadURL: "https://raw/a.mdx"},
+			{Name: "img", Path: "posts/img", Type: "dir"},
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+	t.Run("file", func(t *testing.T) {
+		_, err := decodeContents([]byte(`{"name": "a.mdx", "path": "posts/a.mdx", "type": "file"}`))
+		if !errors.Is(err, errPathIsFile) {
+			t.Fatalf("got %v, want errPathIsFile", err)
+		}
+		if !strings.HasPrefix(err.Error(), "posts/a.mdx: ") {
+			t.Errorf("got %q, want it to name the path", err)
+		}
+	})
+	for name, body := range map[string]string{
+		"not JSON":     `<html>`,
+		"other object": `{"message": "Not Found"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeContents([]byte(body))
+			if err == nil || errors.Is(err, errPathIsFile) {
+				t.Errorf("got %v, want a JSON error", err)
+			}
+		})
+	}
+}
+
+func TestYAMLErrorAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       string
+		firstLine int
+		want      string
+	}{
+		{"offset added", "yaml: line 3: did not find expected key", 2, "yaml: line 4: did not find expected key"},
+		{"frontmatter on line 1", "yaml: line 3: mapping values are not allowed", 1, "yaml: line 3: mapping values are not allowed"},
+		{"every location", "yaml: unmarshal errors:\n  line 1: cannot unmarshal\n  line 12: cannot unmarshal", 10, "yaml: unmarshal errors:\n  line 10: cannot unmarshal\n  line 21: cannot unmarshal"},
+		{"no location", "yaml: control characters are not allowed", 5, "yaml: control characters are not allowed"},
+		{"not a word boundary", "outline 3", 5, "outline 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yamlErrorAt(errors.New(tt.err), tt.firstLine); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Through parsePost, from a real yaml error after comments push the frontmatter down
+	_, err := parsePost("post.mdx", []byte("<!-- draft -->\n\n---\nslug: a\ntitle: [A, B]\n---\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Errorf("got %v, want the error on line 5 of the file", err)
+	}
+}
+
+func TestIsValidColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"#FF79C6", true},
+		{"#ff79c6", true},
+		{"#F7C", true},
+		{"0", true},
+		{"255", true},
+		{"205", true},
+		{"", false},
+		{"256", false},
+		{"-1", false},
+		{"FF79C6", false},
+		{"#FF79C", false},
+		{"#FF79C6A0", false},
+		{"#GG0000", false},
+		{"pink", false},
+		{" 12", false},
+	}
+	for _, tt := range tests {
+		if got := isValidColor(tt.in); got != tt.want {
+			t.Errorf("isValidColor(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSortPosts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	posts := []PostMetadata{
+		{PostTitle: "Short old", PublishDate: day(1), ReadingMinutes: 2},
+		{PostTitle: "Long new", PublishDate: day(4), ReadingMinutes: 9},
+		{PostTitle: "Short new", PublishDate: day(3), ReadingMinutes: 2},
+		{PostTitle: "Undated", ReadingMinutes: 5},
+		{PostTitle: "Medium", PublishDate: day(2), ReadingMinutes: 5},
+	}
+	tests := []struct {
+		mode sortMode
+		want []string
+	}{
+		{sortNewest, []string{"Long new", "Short new", "Medium", "Short old", "Undated"}},
+		{sortOldest, []string{"Short old", "Medium", "Short new", "Long new", "Undated"}},
+		{sortShortest, []string{"Short new", "Short old", "Medium", "Undated", "Long new"}},
+		{sortLongest, []string{"Long new", "Medium", "Undated", "Short new", "Short old"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			sorted := slices.Clone(posts)
+			sortPosts(sorted, tt.mode)
+			if got := titles(sorted); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testModel is a model past the first-run prompt, sized and with posts loaded.
+func testModel(t *testing.T, posts ...PostMetadata) model {
+	t.Helper()
+	return testModelWith(t, options{repo: defaultRepoConfig()}, posts...)
+}
+
+// testModelWith is testModel started with opts.
+func testModelWith(t *testing.T, opts options, posts ...PostMetadata) model {
+	t.Helper()
+	m := initialModel(opts, settings{FirstRunDone: true})
+	m = step(m, tea.WindowSizeMsg{Width: 80, Height: 40})
+	return step(m, postsLoadedMsg{posts: posts})
+}
+
+// step has the model handle msg, dropping the commands it returns.
+func step(m model, msg tea.Msg) model {
+	next, _ := m.Update(msg)
+	return next.(model)
+}
+
+// widestLine is how many columns the longest line of rendered output takes,
+// leaving out trailing padding.
+func widestLine(s string) int {
+	widest := 0
+	for _, line := range strings.Split(ansi.Strip(s), "\n") {
+		widest = max(widest, ansi.StringWidth(strings.TrimRight(line, " ")))
+	}
+	return widest
+}
+
+func TestResizeRenderSeq(t *testing.T) {
+	post := PostMetadata{PostTitle: "Long", Slug: "long", Content: strings.Repeat("word ", 200)}
+	m := testModel(t, post)
+	m.showPost(post)
+	if w := widestLine(m.renderedContent); w > 80 || w < 60 {
+		t.Fatalf("rendered %d columns wide at width 80", w)
+	}
+
+	m = step(m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	stale := m.resizeSeq
+	m = step(m, tea.WindowSizeMsg{Width: 50, Height: 40})
+	if m.resizeSeq == stale {
+		t.Fatal("resizeSeq didn't change on resize")
+	}
+	before := m.renderedContent
+
+	m = step(m, resizeRenderMsg{seq: stale})
+	if m.renderedContent != before {
+		t.Error("a superseded resize re-rendered the post")
+	}
+
+	m = step(m, resizeRenderMsg{seq: m.resizeSeq})
+	if w := widestLine(m.renderedContent); w > 50 || w < 35 {
+		t.Errorf("rendered %d columns wide after settling at width 50", w)
+	}
+}
+
+func TestRenderWidthRaces(t *testing.T) {
+	post := PostMetadata{PostTitle: "Home", Slug: "home", Content: strings.Repeat("word ", 200)}
+	newModel := func() model {
+		m := initialModel(options{repo: defaultRepoConfig(), homeSlug: "home"}, settings{FirstRunDone: true})
+		m.currentScreen = listScreen
+		return m
+	}
+
+	t.Run("posts before the first size", func(t *testing.T) {
+		m := step(newModel(), postsLoadedMsg{posts: []PostMetadata{post}})
+		m = step(m, tea.WindowSizeMsg{Width: 60, Height: 40})
+		if w := widestLine(m.renderedContent); w > 60 || w < 45 {
+			t.Errorf("rendered %d columns wide at width 60", w)
+		}
+	})
+
+	t.Run("size before the posts", func(t *testing.T) {
+		m := step(newModel(), tea.WindowSizeMsg{Width: 60, Height: 40})
+		m = step(m, postsLoadedMsg{posts: []PostMetadata{post}})
+		if w := widestLine(m.renderedContent); w > 60 || w < 45 {
+			t.Errorf("rendered %d columns wide at width 60", w)
+		}
+	})
+
+	t.Run("posts reloaded mid-resize", func(t *testing.T) {
+		m := step(newModel(), tea.WindowSizeMsg{Width: 100, Height: 40})
+		m = step(m, postsLoadedMsg{posts: []PostMetadata{post}})
+		m = step(m, tea.WindowSizeMsg{Width: 50, Height: 40})
+		seq := m.resizeSeq
+		m = step(m, postsLoadedMsg{posts: []PostMetadata{post}})
+		m = step(m, resizeRenderMsg{seq: seq})
+		if w := widestLine(m.renderedContent); w > 50 || w < 35 {
+			t.Errorf("rendered %d columns wide after settling at width 50", w)
+		}
+	})
+}
+
+func TestDuplicateTitles(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	posts := []PostMetadata{
+		{PostTitle: "Hello", Slug: "hello", PublishDate: day(2024, 1, 2), Content: "first"},
+		{PostTitle: "Hello", Slug: "hello-again", PublishDate: day(2024, 1, 2), Content: "second"},
+		{PostTitle: "Hello", PublishDate: day(2023, 5, 5), Content: "third"},
+		{PostTitle: "Other", Slug: "other", PublishDate: day(2022, 1, 1), Content: "fourth"},
+	}
+
+	disambiguated := slices.Clone(posts)
+	disambiguateTitles(disambiguated, isoDate)
+	wantTitles := []string{"Hello (hello)", "Hello (hello-again)", "Hello (2023-05-05)", "Other"}
+	var gotTitles []string
+	for _, p := range disambiguated {
+		gotTitles = append(gotTitles, p.Title())
+	}
+	if !slices.Equal(gotTitles, wantTitles) {
+		t.Errorf("got list titles %q, want %q", gotTitles, wantTitles)
+	}
+
+	keys := make(map[string]bool)
+	for _, p := range posts {
+		keys[p.key()] = true
+	}
+	if len(keys) != len(posts) {
+		t.Errorf("posts share keys: %v", keys)
+	}
+
+	m := testModel(t, posts...)
+	m.currentScreen = listScreen
+	for i, want := range []string{"first", "second", "third"} {
+		m.postList.Select(i)
+		opened := step(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if opened.selectedPost == nil || opened.selectedPost.Content != want {
+			t.Errorf("enter on item %d opened %+v, want the %s post", i, opened.selectedPost, want)
+		}
+
+		// Opening a post directly puts the list cursor on that one
+		m.postList.Select(3)
+		shown := m
+		shown.showPost(posts[i])
+		if shown.postList.Index() != i || shown.selectedPost.Content != want {
+			t.Errorf("showPost(%s post) left the cursor on %d and opened %q", want, shown.postList.Index(), shown.selectedPost.Content)
+		}
+	}
+}
+
+func TestLoadingRetryStatus(t *testing.T) {
+	for _, loaded := range []postsLoadedMsg{
+		{posts: []PostMetadata{{PostTitle: "Post", Slug: "post"}}},
+		{err: errors.New("gave up")},
+	} {
+		m := testModel(t)
+		m.currentScreen = listScreen
+		m.loadPosts()
+		updates := m.fetchProgress.updates
+
+		m = step(m, fetchProgressMsg{attempt: 2, attempts: 4, updates: updates})
+		if view := ansi.Strip(m.View()); !strings.Contains(view, "Retrying (attempt 2/4)…") {
+			t.Errorf("loading screen doesn't show the retry:\n%s", view)
+		}
+		m = step(m, fetchProgressMsg{loaded: 3, total: 5, attempt: 3, attempts: 4, updates: updates})
+		if view := ansi.Strip(m.View()); !strings.Contains(view, "3 of 5 posts · Retrying (attempt 3/4)…") {
+			t.Errorf("loading screen doesn't show progress and the retry:\n%s", view)
+		}
+
+		m = step(m, loaded)
+		m = step(m, fetchProgressMsg{loaded: 5, total: 5, attempt: 3, attempts: 4, updates: updates})
+		if view := ansi.Strip(m.View()); strings.Contains(view, "Retrying") {
+			t.Errorf("retry still shown once loading ended with %v:\n%s", loaded.err, view)
+		}
+		if m.loadPosts(); m.fetchProgress.attempt != 0 {
+			t.Errorf("a new fetch starts at attempt %d", m.fetchProgress.attempt)
+		}
+	}
+}
+
+func TestFetchProgressRetryCleared(t *testing.T) {
+	updates, progress, retrying := newFetchProgress()
+	retrying(2, 4)
+	if msg := <-updates; msg.attempt != 2 || msg.attempts != 4 {
+		t.Errorf("got attempt %d/%d, want 2/4", msg.attempt, msg.attempts)
+	}
+	progress(1, 5)
+	if msg := <-updates; msg.attempt != 0 || msg.loaded != 1 || msg.total != 5 {
+		t.Errorf("after a file loaded got %d of %d, attempt %d; want the retry cleared", msg.loaded, msg.total, msg.attempt)
+	}
+	retrying(1, 4)
+	if msg := <-updates; msg.attempt != 1 || msg.loaded != 1 {
+		t.Errorf("a later retry gave %d loaded, attempt %d; want 1 and 1", msg.loaded, msg.attempt)
+	}
+}
+
+func TestHelpOverlayKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      tea.KeyMsg
+		wantQuit bool
+	}{
+		{"Q", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Q")}, true},
+		{"ctrl+c", tea.KeyMsg{Type: tea.KeyCtrlC}, true},
+		{"q", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, false},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, false},
+		{"?", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(t, PostMetadata{PostTitle: "Post", Slug: "post"})
+			m.currentScreen = listScreen
+			m = step(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+			if !m.showHelp {
+				t.Fatal("? didn't open help")
+			}
+
+			next, cmd := m.Update(tt.key)
+			if next.(model).showHelp {
+				t.Error("help is still open")
+			}
+			quit := false
+			if cmd != nil {
+				_, quit = cmd().(tea.QuitMsg)
+			}
+			if quit != tt.wantQuit {
+				t.Errorf("quit = %t, want %t", quit, tt.wantQuit)
+			}
+			if next.(model).currentScreen != listScreen {
+				t.Errorf("closing help left the list for screen %v", next.(model).currentScreen)
+			}
+		})
+	}
+}
+
+func TestImageFootnotes(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+		urls           []string
+	}{
+		{"with alt", "![A rocket](https://x.example/rocket.png)", `\[image: A rocket\] [1]`, []string{"https://x.example/rocket.png"}},
+		{"without alt", "![](https://x.example/a.png)", `\[image\] [1]`, []string{"https://x.example/a.png"}},
+		{"blank alt", "![  ](https://x.example/a.png)", `\[image\] [1]`, []string{"https://x.example/a.png"}},
+		{"with a title", `![Logo](https://x.example/logo.svg "The logo")`, `\[image: Logo\] [1]`, []string{"https://x.example/logo.svg"}},
+		{
+			"next to a link",
+			"See [the site](https://x.example) ![shot](https://x.example/s.png)",
+			`See the site [1] \[image: shot\] [2]`,
+			[]string{"https://x.example", "https://x.example/s.png"},
+		},
+		{"blank URL", "![alt]( )", "![alt]( )", nil},
+		{"linked image", "[![badge](https://x.example/b.svg)](https://ci.example)", `[\[image: badge\] [1]](https://ci.example)`, []string{"https://x.example/b.svg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, urls := transformLinksToFootnotes(tt.in)
+			if got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+			if !slices.Equal(urls, tt.urls) {
+				t.Errorf("got footnotes %q, want %q", urls, tt.urls)
+			}
+		})
+	}
+}
+
+func TestFindPostBySlug(t *testing.T) {
+	posts := []PostMetadata{
+		{PostTitle: "Old", Slug: "old", Aliases: []string{"launch"}},
+		{PostTitle: "Launch", Slug: "launch-day", Aliases: []string{"launch-2024", "liftoff"}},
+		{PostTitle: "Launch", Slug: "launch"},
+	}
+	tests := []struct {
+		slug, want string
+		wantOK     bool
+	}{
+		{"launch-day", "launch-day", true},
+		{"liftoff", "launch-day", true},
+		{"launch-2024", "launch-day", true},
+		// A post's own slug wins over another's alias, wherever it's listed
+		{"launch", "launch", true},
+		{"nowhere", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := findPostBySlug(posts, tt.slug)
+		if got.Slug != tt.want || ok != tt.wantOK {
+			t.Errorf("findPostBySlug(%q) = %q, %t; want %q, %t", tt.slug, got.Slug, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPrivatePosts(t *testing.T) {
+	posts := []PostMetadata{
+		{PostTitle: "Public", Slug: "public"},
+		{PostTitle: "Secret", Slug: "secret", Private: true},
+		{PostTitle: "Also public", Slug: "also-public"},
+	}
+	if got := titles(publicPosts(posts)); !slices.Equal(got, []string{"Public", "Also public"}) {
+		t.Errorf("publicPosts kept %q", got)
+	}
+	if !posts[1].Private || len(posts) != 3 {
+		t.Error("publicPosts changed the posts it was given")
+	}
+
+	tests := []struct {
+		showPrivate bool
+		want        []string
+	}{
+		{false, []string{"Public", "Also public"}},
+		{true, []string{"Public", "Secret", "Also public"}},
+	}
+	for _, tt := range tests {
+		m := testModelWith(t, options{repo: defaultRepoConfig(), showPrivate: tt.showPrivate}, posts...)
+		var listed []string
+		for _, item := range m.postList.Items() {
+			listed = append(listed, item.(PostMetadata).PostTitle)
+		}
+		if !slices.Equal(listed, tt.want) {
+			t.Errorf("with showPrivate %v the list has %q, want %q", tt.showPrivate, listed, tt.want)
+		}
+		if _, found := findPostBySlug(m.posts, "secret"); found != tt.showPrivate {
+			t.Errorf("with showPrivate %v the private post can be opened: %v", tt.showPrivate, found)
+		}
+	}
+}
+
+func TestHScrollStyledTable(t *testing.T) {
+	var header, rule, row strings.Builder
+	header.WriteString("|")
+	rule.WriteString("|")
+	row.WriteString("|")
+	for i := range 12 {
+		fmt.Fprintf(&header, " col%02d |", i)
+		rule.WriteString("---|")
+		fmt.Fprintf(&row, " **v%02d** |", i)
+	}
+	post := PostMetadata{PostTitle: "Wide", Slug: "wide", Content: header.String() + "\n" + rule.String() + "\n" + row.String() + "\n"}
+	m := testModel(t, post)
+	m.prefs.GlamourStyle = "dark" // Styled, unlike the notty style a test would detect
+	m.hideMetadata = true
+	m.showPost(post)
+	m = step(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+
+	lines := strings.Split(m.renderedContent, "\n")
+	rowIndex := slices.IndexFunc(lines, func(line string) bool { return strings.Contains(ansi.Strip(line), "v00") })
+	if rowIndex < 0 {
+		t.Fatalf("no table row in:\n%s", m.renderedContent)
+	}
+	full := ansi.Strip(lines[rowIndex])
+	if ansi.StringWidth(full) <= m.viewport.Width {
+		t.Fatalf("the table fits in %d columns unwrapped:\n%s", m.viewport.Width, full)
+	}
+	maxOffset := ansi.StringWidth(full) - m.viewport.Width
+
+	for press := 0; press <= maxOffset/hscrollStep+2; press++ {
+		offset := min(press*hscrollStep, maxOffset)
+		line := strings.Split(m.viewport.View(), "\n")[rowIndex]
+		want := strings.TrimRight(ansi.Cut(full, offset, offset+m.viewport.Width), " ")
+		if got := strings.TrimRight(ansi.Strip(line), " "); got != want {
+			t.Errorf("after %d presses showing\n%q\nwant columns %d on\n%q", press, got, offset, want)
+		}
+		// Values still in full view keep their bold, and no style runs on past the line
+		for i := range 12 {
+			value := fmt.Sprintf("v%02d", i)
+			if at := strings.Index(full, value); at >= offset && at+len(value) <= offset+m.viewport.Width {
+				if styled := "\x1b[38;5;252;1m" + value + "\x1b[0m"; !strings.Contains(line, styled) {
+					t.Errorf("after %d presses %s isn't styled in %q", press, value, line)
+				}
+			}
+		}
+		if strings.LastIndex(line, "\x1b[0m") < strings.LastIndex(line, "\x1b[38") {
+			t.Errorf("after %d presses the line ends in a style: %q", press, line)
+		}
+		m = step(m, tea.KeyMsg{Type: tea.KeyRight})
+	}
+
+	for range maxOffset/hscrollStep + 2 {
+		m = step(m, tea.KeyMsg{Type: tea.KeyLeft})
+	}
+	if got := strings.Split(m.viewport.View(), "\n")[rowIndex]; ansi.Strip(got) != ansi.Cut(full, 0, m.viewport.Width) {
+		t.Errorf("scrolled back to %q", ansi.Strip(got))
+	}
+}
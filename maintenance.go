@@ -0,0 +1,41 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	ssh "github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// --- Maintenance mode ---
+
+// maintenanceMode is shared by every session on the server. While it is on,
+// new connections are turned away and existing sessions show a notice instead
+// of the UI, but nobody is disconnected.
+var maintenanceMode atomic.Bool
+
+const maintenanceNotice = "Space Coast Devs BBS is down for maintenance. Back shortly!"
+
+type maintenanceCheckMsg struct{}
+
+// maintenanceCheck polls the shared flag so open sessions notice when it flips.
+func maintenanceCheck() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return maintenanceCheckMsg{}
+	})
+}
+
+// maintenanceMiddleware greets new connections with the notice while maintenance is on.
+func maintenanceMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			if maintenanceMode.Load() {
+				wish.Println(sess, maintenanceNotice)
+				return
+			}
+			next(sess)
+		}
+	}
+}
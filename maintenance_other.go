@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "log"
+
+// watchMaintenanceSignal is a no-op where SIGUSR1 isn't available.
+func watchMaintenanceSignal() {
+	log.Println("Maintenance mode signal (SIGUSR1) is not supported on this platform")
+}
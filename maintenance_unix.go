@@ -0,0 +1,23 @@
+//go:build unix
+
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchMaintenanceSignal toggles maintenance mode each time the process receives SIGUSR1.
+func watchMaintenanceSignal() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1)
+	go func() {
+		for range sigs {
+			on := !maintenanceMode.Load()
+			maintenanceMode.Store(on)
+			log.Printf("Maintenance mode enabled: %t", on)
+		}
+	}()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	glamouransi "github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/term"
+)
+
+// --- Markdown styling ---
+
+// glamourStyleOption maps a style name to the renderer option that selects it.
+// Anything but a built-in name is a JSON style file, from --style.
+func glamourStyleOption(style string) glamour.TermRendererOption {
+	if !slices.Contains(glamourStyles, style) {
+		return glamour.WithStylePath(style)
+	}
+	return glamour.WithStyles(glamourStyleConfig(style))
+}
+
+// glamourStyleConfig resolves a style name the way glamour does, then gives task
+// lists ☐/☑ checkboxes and definition lists indented descriptions. The ASCII
+// styles are left as they are.
+func glamourStyleConfig(style string) glamouransi.StyleConfig {
+	if style == styles.AutoStyle {
+		switch {
+		case !term.IsTerminal(int(os.Stdout.Fd())):
+			style = styles.NoTTYStyle
+		case lipgloss.HasDarkBackground():
+			style = styles.DarkStyle
+		default:
+			style = styles.LightStyle
+		}
+	}
+	base, ok := styles.DefaultStyles[style]
+	if !ok {
+		base = &styles.DarkStyleConfig
+	}
+	// Copy so the shared built-in config is never modified
+	cfg := *base
+	if style == styles.NoTTYStyle || style == styles.AsciiStyle {
+		return cfg
+	}
+
+	// glamour writes the checkbox outside of any element style, so color it here
+	cfg.Task.Ticked = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("☑") + " "
+	cfg.Task.Unticked = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("☐") + " "
+
+	cfg.DefinitionDescription.BlockPrefix = "\n    "
+	return cfg
+}
+
+// boldDefinitionTerms makes the terms of definition lists ("Term" followed by a
+// ": description" line) bold. glamour renders a term's text with the list's
+// style rather than DefinitionTerm, so this is done in the markdown instead.
+func boldDefinitionTerms(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if isFenceCloser(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f, _, ok := fenceOpener(line); ok {
+			fence = f
+			continue
+		}
+		term := strings.TrimSpace(line)
+		isTerm := term != "" && !strings.HasPrefix(term, ":") && !strings.HasPrefix(term, "**") &&
+			i+1 < len(lines) && strings.HasPrefix(lines[i+1], ": ")
+		if isTerm {
+			lines[i] = "**" + term + "**"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimTrailingBlankLines drops the blank lines glamour leaves after a post,
+// which are padded with spaces and styling, so the viewport's bottom is the
+// end of the content. Blank lines between blocks are kept.
+func trimTrailingBlankLines(rendered string) string {
+	lines := strings.Split(rendered, "\n")
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(ansi.Strip(lines[end-1])) == "" {
+		end--
+	}
+	return strings.Join(lines[:end], "\n")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTaskListCheckboxes(t *testing.T) {
+	out, err := renderMarkdown("dark", 80, "- [x] Build\n- [ ] Test\n- [X] Ship\n- [ ] Celebrate\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, line := range strings.Split(ansi.Strip(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			got = append(got, line)
+		}
+	}
+	want := []string{"☑ Build", "☐ Test", "☑ Ship", "☐ Celebrate"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// The ASCII styles keep glamour's own [x] and [ ]
+	out, err = renderMarkdown("ascii", 80, "- [x] Build\n- [ ] Test\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain := ansi.Strip(out); !strings.Contains(plain, "[x] Build") || !strings.Contains(plain, "[ ] Test") {
+		t.Errorf("ascii style rendered %q", plain)
+	}
+}
+
+func TestBoldDefinitionTerms(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"term", "Go\n: A language", "**Go**\n: A language"},
+		{"several", "Go\n: A language\n\nRust\n: Another", "**Go**\n: A language\n\n**Rust**\n: Another"},
+		{"already bold", "**Go**\n: A language", "**Go**\n: A language"},
+		{"no description", "Go\nA language", "Go\nA language"},
+		{"in a code fence", "```\nGo\n: A language\n```", "```\nGo\n: A language\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boldDefinitionTerms(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimTrailingBlankLines(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"blank lines", "Title\n\nBody\n\n\n", "Title\n\nBody"},
+		{"padded and styled", "Title\n\n  Body  \n\x1b[0m    \x1b[0m\n   \n", "Title\n\n  Body  "},
+		{"internal spacing kept", "One\n\n\n\nTwo", "One\n\n\n\nTwo"},
+		{"leading blank lines kept", "\n\nBody\n", "\n\nBody"},
+		{"nothing but blank", "\n  \n\x1b[0m\n", ""},
+		{"no trailing", "Body", "Body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimTrailingBlankLines(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	out, err := renderMarkdown("dark", 40, "# Title\n\nBody\n\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(out, "\n"); strings.TrimSpace(ansi.Strip(lines[len(lines)-1])) != "Body" {
+		t.Errorf("rendered output doesn't end with its last line of text: %q", out)
+	}
+}
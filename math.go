@@ -0,0 +1,158 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// --- Math expressions ---
+
+// mathSymbols maps LaTeX commands to Unicode. Longer names that share a prefix
+// (\leq and \le) are matched whole by mathCommandRe, so order doesn't matter.
+var mathSymbols = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "zeta": "ζ",
+	"eta": "η", "theta": "θ", "iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ",
+	"nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ", "sigma": "σ", "tau": "τ",
+	"upsilon": "υ", "phi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+	"times": "×", "cdot": "·", "div": "÷", "pm": "±", "mp": "∓",
+	"le": "≤", "leq": "≤", "ge": "≥", "geq": "≥", "neq": "≠", "ne": "≠", "approx": "≈", "equiv": "≡",
+	"infty": "∞", "sum": "∑", "prod": "∏", "int": "∫", "partial": "∂", "nabla": "∇", "sqrt": "√",
+	"in": "∈", "notin": "∉", "subset": "⊂", "subseteq": "⊆", "cup": "∪", "cap": "∩", "emptyset": "∅",
+	"forall": "∀", "exists": "∃", "neg": "¬", "land": "∧", "lor": "∨",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "Rightarrow": "⇒", "iff": "⇔", "mapsto": "↦",
+	"ldots": "…", "cdots": "⋯",
+}
+
+var (
+	superscripts = strings.NewReplacer(
+		"0", "⁰", "1", "¹", "2", "²", "3", "³", "4", "⁴", "5", "⁵", "6", "⁶", "7", "⁷", "8", "⁸", "9", "⁹",
+		"+", "⁺", "-", "⁻", "=", "⁼", "(", "⁽", ")", "⁾", "n", "ⁿ", "i", "ⁱ",
+	)
+	subscripts = strings.NewReplacer(
+		"0", "₀", "1", "₁", "2", "₂", "3", "₃", "4", "₄", "5", "₅", "6", "₆", "7", "₇", "8", "₈", "9", "₉",
+		"+", "₊", "-", "₋", "=", "₌", "(", "₍", ")", "₎",
+	)
+
+	mathCommandRe = regexp.MustCompile(`\\([A-Za-z]+)`)
+	mathFracRe    = regexp.MustCompile(`\\frac\{([^{}]*)\}\{([^{}]*)\}`)
+	mathScriptRe  = regexp.MustCompile(`([\^_])(\{[^{}]*\}|[A-Za-z0-9+\-=()])`)
+	// Inline math follows pandoc: no space just inside the dollars and no digit
+	// right after the closing one, so "$5 and $10" stays money
+	inlineMathRe = regexp.MustCompile(`\$([^\s$](?:[^$]*[^\s$])?)\$([^0-9]|$)`)
+	codeSpanRe   = regexp.MustCompile("`[^`]*`")
+	mathWordRe   = regexp.MustCompile(`^([0-9]+|\\[A-Za-z]+)$`)
+)
+
+// mathToUnicode converts simple LaTeX to Unicode: Greek letters and common
+// symbols, \frac{a}{b} as a/b, and super- and subscripts where Unicode has a
+// character for every symbol (otherwise ^ and _ are kept).
+func mathToUnicode(expr string) string {
+	expr = mathFracRe.ReplaceAllStringFunc(expr, func(match string) string {
+		parts := mathFracRe.FindStringSubmatch(match)
+		return groupMath(parts[1]) + "/" + groupMath(parts[2])
+	})
+	expr = mathScriptRe.ReplaceAllStringFunc(expr, func(match string) string {
+		parts := mathScriptRe.FindStringSubmatch(match)
+		body := strings.TrimSuffix(strings.TrimPrefix(parts[2], "{"), "}")
+		replacer := superscripts
+		if parts[1] == "_" {
+			replacer = subscripts
+		}
+		converted := replacer.Replace(body)
+		for _, r := range converted {
+			if r < 0x80 { // Some symbol has no script form
+				return parts[1] + groupMath(body)
+			}
+		}
+		return converted
+	})
+	expr = mathCommandRe.ReplaceAllStringFunc(expr, func(match string) string {
+		if symbol, ok := mathSymbols[match[1:]]; ok {
+			return symbol
+		}
+		return match
+	})
+	expr = strings.NewReplacer(`\,`, " ", `\;`, " ", `\!`, "", `\{`, "{", `\}`, "}").Replace(expr)
+	return strings.ReplaceAll(strings.ReplaceAll(expr, "{", ""), "}", "")
+}
+
+// groupMath parenthesizes a multi-character operand so a/b or x^ab can't be
+// misread once the braces are gone.
+func groupMath(operand string) string {
+	if len(operand) <= 1 || mathWordRe.MatchString(operand) {
+		return operand
+	}
+	return "(" + operand + ")"
+}
+
+// renderMath rewrites $$...$$ blocks as fenced code blocks and $...$ spans as
+// code spans, both converted with mathToUnicode. Fenced code and code spans
+// are left alone.
+func renderMath(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		if fence, _, ok := fenceOpener(lines[i]); ok {
+			end := i + 1
+			for end < len(lines) && !isFenceCloser(lines[end], fence) {
+				end++
+			}
+			out = append(out, lines[i:min(end+1, len(lines))]...)
+			i = end
+			continue
+		}
+
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "$$") {
+			// A block runs to the line ending in $$, which may be this one
+			block := []string{strings.TrimPrefix(trimmed, "$$")}
+			end := i
+			for !strings.HasSuffix(strings.TrimSpace(block[len(block)-1]), "$$") && end+1 < len(lines) {
+				end++
+				block = append(block, lines[end])
+			}
+			last := strings.TrimSpace(block[len(block)-1])
+			if !strings.HasSuffix(last, "$$") {
+				out = append(out, lines[i]) // Unclosed, leave it as text
+				continue
+			}
+			block[len(block)-1] = strings.TrimSuffix(last, "$$")
+			out = append(out, "```")
+			for _, line := range block {
+				if line = strings.TrimSpace(line); line != "" {
+					out = append(out, mathToUnicode(line))
+				}
+			}
+			out = append(out, "```")
+			i = end
+			continue
+		}
+
+		out = append(out, renderInlineMath(lines[i]))
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderInlineMath converts the $...$ spans of one line, outside code spans.
+func renderInlineMath(line string) string {
+	var b strings.Builder
+	rest := line
+	for {
+		loc := codeSpanRe.FindStringIndex(rest)
+		text := rest
+		if loc != nil {
+			text = rest[:loc[0]]
+		}
+		b.WriteString(inlineMathRe.ReplaceAllStringFunc(text, func(match string) string {
+			parts := inlineMathRe.FindStringSubmatch(match)
+			return "`" + mathToUnicode(parts[1]) + "`" + parts[2]
+		}))
+		if loc == nil {
+			return b.String()
+		}
+		b.WriteString(rest[loc[0]:loc[1]])
+		rest = rest[loc[1]:]
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestMathToUnicode(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`\alpha + \beta`, "α + β"},
+		{`x^2 + y^{10}`, "x² + y¹⁰"},
+		{`a_1 + a_{n+1}`, "a₁ + a_(n+1)"},
+		{`e^{i\pi}`, "e^(iπ)"}, // π has no superscript
+		{`x^{ab}`, "x^(ab)"},
+		{`\frac{1}{2}`, "1/2"},
+		{`\frac{a+b}{c}`, "(a+b)/c"},
+		{`a \leq b \le c \neq d`, "a ≤ b ≤ c ≠ d"},
+		{`\sum_{i=0}^{n} x_i`, "∑_(i=0)ⁿ x_i"}, // Nor has i a subscript
+		{`\unknown{x}`, `\unknownx`},
+		{`\{a, b\}`, "a, b"},
+	}
+	for _, tt := range tests {
+		if got := mathToUnicode(tt.in); got != tt.want {
+			t.Errorf("mathToUnicode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMath(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"inline", `Euler: $e^{i\pi} + 1 = 0$.`, "Euler: `e^(iπ) + 1 = 0`."},
+		{"several inline", `$\alpha$ and $\beta$`, "`α` and `β`"},
+		{"money", "$5 and $10", "$5 and $10"},
+		{"space inside", "$ x $", "$ x $"},
+		{"code span", "`$x^2$` and $x^2$", "`$x^2$` and `x²`"},
+		{"block on one line", `$$\pi r^2$$`, "```\nπ r²\n```"},
+		{"block over lines", "$$\n\\alpha\n\\beta\n$$", "```\nα\nβ\n```"},
+		{"unclosed block", "$$\nx^2\nmore", "$$\nx^2\nmore"},
+		{"code fence", "```\n$x^2$\n$$y$$\n```", "```\n$x^2$\n$$y$$\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMath(tt.in); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// --- MDX stripping ---
+
+// mdxCommentRe matches a JSX comment on one line.
+var mdxCommentRe = regexp.MustCompile(`\{/\*.*?\*/\}`)
+
+// stripTags removes what only MDX understands from a post, so glamour sees
+// plain markdown: import and export lines, JSX comments, and the opening,
+// closing and self-closing tags of components, which MDX tells from HTML by
+// their capital letter. Tags may span lines but not a blank line; one that
+// never closes was prose, as in a<B, and is kept. The text between a paired
+// component's tags stays. Code blocks and code spans are left alone, and so
+// is lowercase HTML, which glamour drops by itself.
+func stripTags(content string) string {
+	lines := strings.Split(content, "\n")
+	var kept []string
+	fence := ""
+	var tag tagScanner // Set while inside a component tag continued from an earlier line
+	for i := 0; i < len(lines) || tag.open; i++ {
+		var line string
+		from := 0
+		if tag.open && (i == len(lines) || strings.TrimSpace(lines[i]) == "") {
+			// The tag never closed: put the text back and go on after the <
+			i, kept = tag.line, kept[:tag.kept]
+			line, from = tag.before+"<"+tag.after, len(tag.before)+1
+			tag = tagScanner{}
+		} else {
+			line = lines[i]
+			if tag.open {
+				line = tag.skip(line)
+				if tag.open {
+					continue
+				}
+			} else if fence != "" {
+				if isFenceCloser(line, fence) {
+					fence = ""
+				}
+				kept = append(kept, line)
+				continue
+			} else if f, _, ok := fenceOpener(line); ok {
+				fence = f
+				kept = append(kept, line)
+				continue
+			} else if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "import ") || strings.HasPrefix(trimmed, "export ") {
+				continue
+			}
+			line = mdxCommentRe.ReplaceAllString(line, "")
+		}
+		line = tag.strip(line, from)
+		if tag.open {
+			tag.line, tag.kept = i, len(kept)
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// tagScanner follows a component tag to its closing >, past > in quoted
+// attributes and {expressions} such as onClick={() => go()}.
+type tagScanner struct {
+	open  bool
+	quote rune // The quote an attribute value is open in, or 0
+	depth int  // How many braces deep the tag is
+
+	// Where the open tag began, to put it back if it never closes
+	line   int    // The line it is on
+	kept   int    // How many lines were kept before that line
+	before string // The line's text before the <, already stripped
+	after  string // The line's text after the <
+}
+
+// strip removes the component tags from a line outside code spans, starting
+// at from; the text before it is kept as it is. If the line ends inside a
+// tag, the scanner is left open for the next line.
+func (t *tagScanner) strip(line string, from int) string {
+	var out strings.Builder
+	out.WriteString(line[:from])
+	for i := from; i < len(line); {
+		switch {
+		case line[i] == '`':
+			// Copy a code span through its closing run of as many backticks
+			run := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+			end := strings.Index(line[i+run:], line[i:i+run])
+			if end < 0 {
+				out.WriteString(line[i:])
+				return out.String()
+			}
+			out.WriteString(line[i : i+run+end+run])
+			i += run + end + run
+		case isComponentTag(line[i:]):
+			rest := t.skip(line[i+1:])
+			if t.open {
+				t.before, t.after = out.String(), line[i+1:]
+				return out.String()
+			}
+			i = len(line) - len(rest)
+		default:
+			out.WriteByte(line[i])
+			i++
+		}
+	}
+	return out.String()
+}
+
+// skip consumes s up to and including the > closing the tag, returning what
+// follows. If s ends first, the tag is still open.
+func (t *tagScanner) skip(s string) string {
+	t.open = true
+	for i, r := range s {
+		switch {
+		case t.quote != 0:
+			if r == t.quote {
+				t.quote = 0
+			}
+		case r == '"' || r == '\'' || (r == '`' && t.depth > 0):
+			t.quote = r
+		case r == '{':
+			t.depth++
+		case r == '}':
+			t.depth = max(0, t.depth-1)
+		case r == '>' && t.depth == 0:
+			*t = tagScanner{}
+			return s[i+1:]
+		}
+	}
+	return ""
+}
+
+// isComponentTag reports whether s starts with a component's tag: < or </
+// followed by a capital letter, as in <Figure or </CallToAction, then more
+// letters, digits or dots, then whitespace, / or > or the end of the line.
+func isComponentTag(s string) bool {
+	s, ok := strings.CutPrefix(s, "<")
+	if !ok {
+		return false
+	}
+	s = strings.TrimPrefix(s, "/")
+	for i, r := range s {
+		switch {
+		case i == 0:
+			if !unicode.IsUpper(r) {
+				return false
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.':
+		default:
+			return unicode.IsSpace(r) || r == '/' || r == '>'
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"self-closing", "Before <Image src=\"a.png\" /> after", "Before  after"},
+		{"paired keeps text", "<Callout type=\"tip\">Read this</Callout>", "Read this"},
+		{"nested", "<Tabs><Tab label=\"Go\">Go code</Tab><Tab label=\"Rust\">Rust code</Tab></Tabs>", "Go codeRust code"},
+		{
+			"nested over lines",
+			"<Tabs>\n  <Tab label=\"Go\">\n    Go code\n  </Tab>\n</Tabs>",
+			"\n  \n    Go code\n  \n",
+		},
+		{"tag spanning lines", "<Figure\n  src=\"a.png\"\n  alt=\"x > y\"\n/>\nCaption", "\n\nCaption"},
+		{"braces in attributes", "<Button onClick={() => go(a > b)}>Go</Button>", "Go"},
+		{"imports and exports", "import Chart from './Chart'\nexport const meta = {}\nText", "Text"},
+		{"JSX comment", "Text {/* todo */}more", "Text more"},
+		{"lowercase HTML kept", "<div>Text</div>", "<div>Text</div>"},
+		{"code span", "Use `<Foo />` here", "Use `<Foo />` here"},
+		{"code fence", "```jsx\n<Foo bar />\nimport x from 'y'\n```\n<Foo />", "```jsx\n<Foo bar />\nimport x from 'y'\n```\n"},
+		{"tilde fence", "~~~\n<Foo>\n~~~", "~~~\n<Foo>\n~~~"},
+		{"less than", "a < B and 1 <2", "a < B and 1 <2"},
+		{
+			"unclosed tag in prose",
+			"Compare a<B and more\n\nSecond paragraph\n\nThird",
+			"Compare a<B and more\n\nSecond paragraph\n\nThird",
+		},
+		{"unclosed tag at the end", "Keep <Figure\n  src=\"a.png\"", "Keep <Figure\n  src=\"a.png\""},
+		{"tag after an unclosed one", "x<B then\n\n<Note>kept</Note>", "x<B then\n\nkept"},
+		{"no spaces", "if a<B, b<C2d or c<D.e\n\nEnd", "if a<B, b<C2d or c<D.e\n\nEnd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripTags(tt.in); got != tt.want {
+				t.Errorf("stripTags(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// --- SSH message of the day ---
+
+// loadMOTD reads the --motd-file banner. ANSI art is usually saved as CP437
+// with a SAUCE metadata record after a DOS end-of-file byte, so everything from
+// that byte on is dropped and text that isn't UTF-8 is decoded as CP437.
+func loadMOTD(path string) (string, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if i := bytes.IndexByte(body, 0x1a); i >= 0 {
+		body = body[:i]
+	}
+	if !utf8.Valid(body) {
+		if body, err = charmap.CodePage437.NewDecoder().Bytes(body); err != nil {
+			return "", err
+		}
+	}
+	// DOS line endings would leave a carriage return in every line of the splash
+	return string(bytes.ReplaceAll(body, []byte("\r\n"), []byte("\n"))), nil
+}
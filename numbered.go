@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Numbered list items ---
+
+// numberedDelegate renders list items like its DefaultDelegate, described by
+// the description template, optionally prefixed with their 1-based position
+// among the visible items, so numbers follow the current sort and filter, and
+// with a dot when the reader hasn't opened them.
+type numberedDelegate struct {
+	list.DefaultDelegate
+	numbered    bool
+	reads       map[string]postRead // The reader's stats.Reads; nil marks nothing
+	description *template.Template  // Renders the line under each post
+	formatDate  dateFormat          // Writes the dates in it
+}
+
+func (d numberedDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	item = asSearchResult(item, m)
+	if p, ok := item.(PostMetadata); ok {
+		item = describedPost{PostMetadata: p, description: d.description, formatDate: d.formatDate}
+	}
+	if !d.numbered && d.reads == nil {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+
+	first, rest := "", "" // Prefixes of the item's first line and of the others
+	if d.numbered {
+		// Right-align numbers to the widest one so "9." and "10." line up
+		digits := len(strconv.Itoa(len(m.VisibleItems())))
+		numberStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+		first += numberStyle.Render(fmt.Sprintf("%*d. ", digits, index+1))
+		rest += strings.Repeat(" ", digits+2)
+	}
+	if d.reads != nil {
+		first += unreadMark(d.reads, item)
+		rest += "  "
+	}
+	var b strings.Builder
+	m.SetWidth(m.Width() - len(rest)) // m is a copy; leave room for the prefix
+	d.DefaultDelegate.Render(&b, m, index, item)
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		lines[i] = prefix + line
+	}
+	fmt.Fprint(w, strings.Join(lines, "\n"))
+}
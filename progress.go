@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Fetch progress ---
+
+// fetchProgressMsg reports how many of the post files being fetched are
+// done, and the latest retry of one of its requests if no file has been done
+// since.
+type fetchProgressMsg struct {
+	loaded, total     int
+	attempt, attempts int                   // Attempt the latest retry is, out of all allowed; 0 when none is pending
+	updates           chan fetchProgressMsg // The fetch it's from, so a superseded one's reports are ignored
+}
+
+// progressFunc is told each time a fetch finishes a post file, whether or
+// not it loaded.
+type progressFunc func(loaded, total int)
+
+func (f progressFunc) report(loaded, total int) {
+	if f != nil {
+		f(loaded, total)
+	}
+}
+
+// retryFunc is told each time doWithRetry retries a request, with the attempt
+// it's about to make out of all it may.
+type retryFunc func(attempt, attempts int)
+
+func (f retryFunc) report(attempt, attempts int) {
+	if f != nil {
+		f(attempt, attempts)
+	}
+}
+
+// newFetchProgress returns a channel for a fetch's progress and the functions
+// reporting files done and retries to it. Each report carries everything so
+// far, so one the model hasn't taken yet is replaced by the next instead of
+// holding up the fetch.
+func newFetchProgress() (chan fetchProgressMsg, progressFunc, retryFunc) {
+	updates := make(chan fetchProgressMsg, 1)
+	var mu sync.Mutex
+	latest := fetchProgressMsg{updates: updates}
+	send := func(update func(*fetchProgressMsg)) {
+		mu.Lock()
+		defer mu.Unlock()
+		update(&latest)
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- latest
+	}
+	progress := func(loaded, total int) {
+		// A file done means the retry before it got through
+		send(func(msg *fetchProgressMsg) { msg.loaded, msg.total, msg.attempt = loaded, total, 0 })
+	}
+	retrying := func(attempt, attempts int) {
+		send(func(msg *fetchProgressMsg) { msg.attempt, msg.attempts = attempt, attempts })
+	}
+	return updates, progress, retrying
+}
+
+// waitForProgress waits for a fetch's next report, until the fetch closes
+// the channel.
+func waitForProgress(updates chan fetchProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math/rand/v2"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Surprise me ---
+
+// unreadWeight is how many times likelier an unread post is picked than a
+// read one with --random-unread.
+const unreadWeight = 4
+
+// randomPost picks one of posts at random, each as likely as its weight
+// says; posts weighing nothing are never picked. It reports false when
+// none can be.
+func randomPost(posts []PostMetadata, rng *rand.Rand, weight func(PostMetadata) int) (PostMetadata, bool) {
+	total := 0
+	for _, p := range posts {
+		total += weight(p)
+	}
+	if total == 0 {
+		return PostMetadata{}, false
+	}
+	n := rng.IntN(total)
+	for _, p := range posts {
+		if n -= weight(p); n < 0 {
+			return p, true
+		}
+	}
+	return PostMetadata{}, false // Unreachable while weights don't change
+}
+
+// openRandomPost opens a random post out of those the list shows, so an
+// applied filter narrows the choice, favoring unread ones with
+// --random-unread.
+func (m *model) openRandomPost() tea.Cmd {
+	var posts []PostMetadata
+	for _, item := range m.postList.VisibleItems() {
+		posts = append(posts, item.(PostMetadata))
+	}
+	weight := func(PostMetadata) int { return 1 }
+	if m.opts.randomUnread {
+		weight = func(p PostMetadata) int {
+			if _, read := m.stats.Reads[p.key()]; read {
+				return 1
+			}
+			return unreadWeight
+		}
+	}
+	p, ok := randomPost(posts, m.rng, weight)
+	if !ok {
+		if m.postList.FilterState() != list.Unfiltered {
+			return m.setStatus("No posts match the filter")
+		}
+		return m.setStatus("No posts to pick from")
+	}
+	return m.showPost(p)
+}
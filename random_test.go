@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRandomPost(t *testing.T) {
+	posts := []PostMetadata{{Slug: "read"}, {Slug: "unread"}, {Slug: "skipped"}}
+	weight := func(p PostMetadata) int {
+		switch p.Slug {
+		case "read":
+			return 1
+		case "unread":
+			return unreadWeight
+		}
+		return 0
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	picked := make(map[string]int)
+	for range 1000 {
+		p, ok := randomPost(posts, rng, weight)
+		if !ok {
+			t.Fatal("picked nothing")
+		}
+		picked[p.Slug]++
+	}
+	if picked["skipped"] != 0 {
+		t.Errorf("picked a post weighing nothing %d times", picked["skipped"])
+	}
+	if picked["unread"] < 3*picked["read"] {
+		t.Errorf("picked unread %d times and read %d, want about %d times as often", picked["unread"], picked["read"], unreadWeight)
+	}
+
+	if _, ok := randomPost(nil, rng, weight); ok {
+		t.Error("picked from no posts")
+	}
+}
+
+func TestOpenRandomPostFiltered(t *testing.T) {
+	posts := []PostMetadata{
+		{PostTitle: "Goroutines", Slug: "goroutines", Category: "Golang"},
+		{PostTitle: "Generics", Slug: "generics", Category: "Golang"},
+		{PostTitle: "Liftoff", Slug: "liftoff", Category: "Space"},
+		{PostTitle: "Splashdown", Slug: "splashdown", Category: "Space"},
+	}
+	for _, randomUnread := range []bool{false, true} {
+		m := testModelWith(t, options{repo: defaultRepoConfig(), randomUnread: randomUnread}, posts...)
+		m.rng = rand.New(rand.NewPCG(1, 2))
+		m.currentScreen = listScreen
+		m.postList.SetFilterText("golang")
+		if n := len(m.postList.VisibleItems()); n != 2 {
+			t.Fatalf("filter shows %d posts, want 2", n)
+		}
+		// Reading one Golang post leaves the Space ones the only unread posts
+		// outside the filter, which weighting mustn't reach
+		m.stats.recordRead(posts[0])
+
+		opened := make(map[string]int)
+		for range 100 {
+			m.openRandomPost()
+			if m.selectedPost == nil {
+				t.Fatal("opened nothing")
+			}
+			opened[m.selectedPost.Slug]++
+			m.currentScreen = listScreen
+		}
+		if opened["liftoff"]+opened["splashdown"] != 0 {
+			t.Errorf("with randomUnread %v, opened posts outside the filter: %v", randomUnread, opened)
+		}
+		if opened["goroutines"] == 0 || opened["generics"] == 0 {
+			t.Errorf("with randomUnread %v, never opened one of the filtered posts: %v", randomUnread, opened)
+		}
+	}
+
+	m := testModel(t, posts...)
+	m.currentScreen = listScreen
+	m.postList.SetFilterText("nothing like it")
+	m.openRandomPost()
+	if m.selectedPost != nil {
+		t.Errorf("opened %q with nothing matching the filter", m.selectedPost.Slug)
+	}
+}
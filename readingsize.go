@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- Reading size ---
+
+// readingSize stands in for a font size, which only the terminal controls: it
+// narrows the reading column and, at its smallest, spaces paragraphs out.
+type readingSize int
+
+const (
+	readingFull readingSize = iota
+	readingComfortable
+	readingNarrow
+	numReadingSizes
+)
+
+func (s readingSize) String() string {
+	switch s {
+	case readingComfortable:
+		return "comfortable"
+	case readingNarrow:
+		return "narrow"
+	default:
+		return "full width"
+	}
+}
+
+// maxWidth caps the reading column, 0 meaning the viewport's full width.
+func (s readingSize) maxWidth() int {
+	switch s {
+	case readingComfortable:
+		return 80
+	case readingNarrow:
+		return 64
+	default:
+		return 0
+	}
+}
+
+// spaceParagraphs doubles every blank line of rendered content, leaving extra
+// room between paragraphs.
+func spaceParagraphs(rendered string) string {
+	lines := strings.Split(rendered, "\n")
+	spaced := make([]string, 0, len(lines))
+	for _, line := range lines {
+		spaced = append(spaced, line)
+		if strings.TrimSpace(ansi.Strip(line)) == "" {
+			spaced = append(spaced, line)
+		}
+	}
+	return strings.Join(spaced, "\n")
+}
+
+// indentLines shifts every line right by n columns.
+func indentLines(content string, n int) string {
+	if n <= 0 {
+		return content
+	}
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(content, "\n", "\n"+pad)
+}
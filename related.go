@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// --- Related posts ---
+
+const maxRelatedPosts = 3
+
+// relatedIndex maps each tag and category to the posts (by index) carrying it,
+// so finding related posts only touches posts that share something.
+type relatedIndex map[string][]int
+
+func tagKey(tag string) string      { return "tag:" + strings.ToLower(tag) }
+func categoryKey(cat string) string { return "cat:" + strings.ToLower(cat) }
+
+// buildRelatedIndex indexes posts by their tags and category.
+func buildRelatedIndex(posts []PostMetadata) relatedIndex {
+	idx := make(relatedIndex)
+	for i, p := range posts {
+		for _, tag := range p.Tags {
+			idx[tagKey(tag)] = append(idx[tagKey(tag)], i)
+		}
+		if p.Category != "" {
+			idx[categoryKey(p.Category)] = append(idx[categoryKey(p.Category)], i)
+		}
+	}
+	return idx
+}
+
+// related returns the indexes of up to maxRelatedPosts posts sharing the most
+// tags with p, with a matching category counting as one more shared tag. The
+// post itself is excluded; ties go to the newer post.
+func (idx relatedIndex) related(posts []PostMetadata, p PostMetadata) []int {
+	scores := make(map[int]int)
+	for _, tag := range p.Tags {
+		for _, i := range idx[tagKey(tag)] {
+			scores[i]++
+		}
+	}
+	if p.Category != "" {
+		for _, i := range idx[categoryKey(p.Category)] {
+			scores[i]++
+		}
+	}
+
+	var candidates []int
+	for i := range scores {
+		if posts[i].key() != p.key() {
+			candidates = append(candidates, i)
+		}
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		ia, ib := candidates[a], candidates[b]
+		if scores[ia] != scores[ib] {
+			return scores[ia] > scores[ib]
+		}
+		return posts[ia].PublishDate.After(posts[ib].PublishDate)
+	})
+
+	if len(candidates) > maxRelatedPosts {
+		candidates = candidates[:maxRelatedPosts]
+	}
+	return candidates
+}
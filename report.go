@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Post reporting for moderated BBS deployments ---
+const (
+	maxReportsPerSession = 5
+	reportCooldown       = 30 * time.Second
+)
+
+// postReport is the JSON body POSTed to the operator's report webhook.
+type postReport struct {
+	Slug       string    `json:"slug"` // The post's key(), a hash of its title when it has no slug
+	Title      string    `json:"title"`
+	Reason     string    `json:"reason,omitempty"`
+	ReportedAt time.Time `json:"reportedAt"`
+}
+
+type reportSentMsg struct {
+	err error
+}
+
+// sendReportCmd submits a report to the webhook in the background.
+func sendReportCmd(webhookURL string, report postReport, minTLS uint16) tea.Cmd {
+	return func() tea.Msg {
+		body, err := json.Marshal(report)
+		if err != nil {
+			return reportSentMsg{err: fmt.Errorf("encoding report: %w", err)}
+		}
+
+		client := newHTTPClient(10*time.Second, minTLS)
+		req, err := http.NewRequestWithContext(context.Background(), "POST", webhookURL, bytes.NewReader(body))
+		if err != nil {
+			return reportSentMsg{err: fmt.Errorf("creating report request: %w", err)}
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return reportSentMsg{err: fmt.Errorf("sending report: %w", err)}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return reportSentMsg{err: fmt.Errorf("sending report: status %s", resp.Status)}
+		}
+		return reportSentMsg{}
+	}
+}
+
+// canReport applies the per-session rate limit, returning a user-facing reason when blocked.
+func (m model) canReport(now time.Time) (bool, string) {
+	if m.reportsSent >= maxReportsPerSession {
+		return false, "Report limit reached for this session"
+	}
+	if !m.lastReport.IsZero() && now.Sub(m.lastReport) < reportCooldown {
+		wait := reportCooldown - now.Sub(m.lastReport)
+		return false, fmt.Sprintf("Please wait %ds before sending another report", int(wait.Seconds())+1)
+	}
+	return true, ""
+}
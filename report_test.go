@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reportSent runs cmd, and in order the commands of any batch it returns,
+// until one sends a report. Those after it, like the status timer, don't run.
+func reportSent(cmd tea.Cmd) (reportSentMsg, bool) {
+	if cmd == nil {
+		return reportSentMsg{}, false
+	}
+	switch msg := cmd().(type) {
+	case reportSentMsg:
+		return msg, true
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if sent, ok := reportSent(c); ok {
+				return sent, true
+			}
+		}
+	}
+	return reportSentMsg{}, false
+}
+
+func TestSendReport(t *testing.T) {
+	var failing atomic.Bool
+	reports := make(chan postReport, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report postReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Error(err)
+		}
+		reports <- report
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	unslugged := PostMetadata{PostTitle: "No slug"}
+	m := testModelWith(t, options{repo: defaultRepoConfig(), reportWebhook: srv.URL}, unslugged)
+	m.showPost(unslugged)
+	send := func() reportSentMsg {
+		t.Helper()
+		m.reporting = true
+		m.lastReport = time.Time{} // Past the cooldown
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = next.(model)
+		sent, ok := reportSent(cmd)
+		if !ok {
+			t.Fatal("enter sent no report")
+		}
+		m = step(m, sent)
+		return sent
+	}
+
+	failing.Store(true)
+	if sent := send(); sent.err == nil {
+		t.Error("a failed report came back sent")
+	}
+	if got := <-reports; got.Slug != unslugged.key() || got.Title != "No slug" {
+		t.Errorf("reported %+v, want slug %q", got, unslugged.key())
+	}
+	if m.reportsSent != 0 {
+		t.Errorf("a failed report counted, %d sent", m.reportsSent)
+	}
+
+	failing.Store(false)
+	for range maxReportsPerSession {
+		if sent := send(); sent.err != nil {
+			t.Fatal(sent.err)
+		}
+		<-reports
+	}
+	if m.reportsSent != maxReportsPerSession {
+		t.Errorf("%d reports counted, want %d", m.reportsSent, maxReportsPerSession)
+	}
+	if ok, _ := m.canReport(time.Now().Add(time.Hour)); ok {
+		t.Error("can report past the limit")
+	}
+}
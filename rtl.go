@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- Right-to-left posts ---
+
+// rtlScripts are the scripts written right to left that posts are likely to use.
+var rtlScripts = []*unicode.RangeTable{unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko}
+
+// rightToLeft reports whether the post should be laid out right to left: as
+// its dir frontmatter says, or when it has none, when most of its letters are
+// in a right-to-left script.
+func (p PostMetadata) rightToLeft() bool {
+	switch strings.ToLower(p.Dir) {
+	case "rtl":
+		return true
+	case "ltr":
+		return false
+	}
+
+	letters, rtl := 0, 0
+	for _, r := range p.PostTitle + p.Content {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.In(r, rtlScripts...) {
+			rtl++
+		}
+	}
+	return rtl*2 > letters
+}
+
+// alignRight moves each rendered line's trailing padding to its front, so the
+// text ends at the right edge of the column. Terminals still lay out the
+// characters themselves; the few that implement bidi reorder them.
+func alignRight(rendered string) string {
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		visible := ansi.Strip(line)
+		text := strings.TrimRight(visible, " ")
+		pad := ansi.StringWidth(visible) - ansi.StringWidth(text)
+		if text == "" || pad == 0 {
+			continue
+		}
+		lines[i] = strings.Repeat(" ", pad) + ansi.Truncate(line, ansi.StringWidth(text), "")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestRightToLeft(t *testing.T) {
+	tests := []struct {
+		name string
+		post PostMetadata
+		want bool
+	}{
+		{"dir rtl", PostMetadata{Dir: "rtl", Content: "All English"}, true},
+		{"dir RTL", PostMetadata{Dir: "RTL", Content: "All English"}, true},
+		{"dir ltr", PostMetadata{Dir: "ltr", Content: "שלום עולם"}, false},
+		{"Hebrew", PostMetadata{PostTitle: "שלום", Content: "שלום עולם, with English"}, true},
+		{"Arabic", PostMetadata{Content: "مرحبا بالعالم"}, true},
+		{"English quoting Hebrew", PostMetadata{Content: "They said שלום and went on their way"}, false},
+		{"exactly half", PostMetadata{Content: "abcd אבגד"}, false},
+		{"digits and punctuation don't count", PostMetadata{Content: "אבג 1234567890 !!! ab"}, true},
+		{"title counts", PostMetadata{PostTitle: "Hello there", Content: "אבג"}, false},
+		{"no letters", PostMetadata{Content: "1 + 1 = 2"}, false},
+		{"unknown dir is detected", PostMetadata{Dir: "auto", Content: "שלום עולם"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.post.rightToLeft(); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
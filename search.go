@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Full-text search ---
+
+// searchFilter is the list's filter over PostMetadata.FilterValue: the usual
+// fuzzy match over titles, categories and tags, then posts whose text
+// contains the term.
+func searchFilter(term string, targets []string) []list.Rank {
+	meta := make([]string, len(targets))
+	bodies := make([]string, len(targets))
+	for i, target := range targets {
+		meta[i], bodies[i], _ = strings.Cut(target, "\n")
+	}
+	ranks := list.DefaultFilter(term, meta)
+	matched := make(map[int]bool, len(ranks))
+	for _, r := range ranks {
+		matched[r.Index] = true
+	}
+	for i, body := range bodies {
+		if !matched[i] && indexFold(body, term) >= 0 {
+			ranks = append(ranks, list.Rank{Index: i})
+		}
+	}
+	return ranks
+}
+
+var (
+	plainLinkRe     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	plainLineMarkRe = regexp.MustCompile(`(?m)^\s*(#{1,6}\s+|>\s*|[-*+]\s+|\d+[.)]\s+)`)
+	plainFenceRe    = regexp.MustCompile("(?m)^\\s*(```|~~~).*$")
+	plainEmphasisRe = regexp.MustCompile("\\*+|`+|~~")
+)
+
+// plainText reduces markdown to its words on one line, for searching and
+// quoting: link and image text stay, markup and MDX go.
+func plainText(markdown string) string {
+	text := stripTags(markdown)
+	text = plainFenceRe.ReplaceAllString(text, "")
+	text = plainLinkRe.ReplaceAllString(text, "$1")
+	text = plainLineMarkRe.ReplaceAllString(text, "")
+	text = plainEmphasisRe.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// indexFold is strings.Index ignoring case, or -1 when term is empty.
+func indexFold(s, term string) int {
+	if term == "" {
+		return -1
+	}
+	return strings.Index(strings.ToLower(s), strings.ToLower(term))
+}
+
+const (
+	// snippetBefore and snippetAfter are how many characters of context a
+	// search snippet keeps around the first match.
+	snippetBefore = 30
+	snippetAfter  = 60
+
+	// Bold on and off without a full reset, so the description's own colors survive.
+	boldOn  = "\x1b[1m"
+	boldOff = "\x1b[22m"
+)
+
+// searchSnippet quotes text around the first match of term, with the match
+// in bold and ellipses where the text was cut. It also returns how many
+// times term occurs, 0 if not at all.
+func searchSnippet(text, term string) (string, int) {
+	if term == "" {
+		return "", 0
+	}
+	lower, lowerTerm := strings.ToLower(text), strings.ToLower(term)
+	if len(lower) != len(text) || len(lowerTerm) != len(term) {
+		// Lowercasing changed byte lengths (rare letters like İ), so offsets
+		// into lower wouldn't fit text; match case-sensitively instead
+		lower, lowerTerm = text, term
+	}
+	start := strings.Index(lower, lowerTerm)
+	if start < 0 {
+		return "", 0
+	}
+	count := strings.Count(lower, lowerTerm)
+	end := start + len(lowerTerm)
+
+	from := start
+	for n := 0; n < snippetBefore && from > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	to := end
+	for n := 0; n < snippetAfter && to < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+	// Start and stop on word boundaries when cutting into the text
+	if from > 0 {
+		if i := strings.IndexByte(text[from:start], ' '); i >= 0 {
+			from += i + 1
+		}
+	}
+	if to < len(text) {
+		if i := strings.LastIndexByte(text[end:to], ' '); i >= 0 {
+			to = end + i
+		}
+	}
+
+	snippet := text[from:start] + boldOn + text[start:end] + boldOff + text[end:to]
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(text) {
+		snippet += "…"
+	}
+	return snippet, count
+}
+
+// searchResultItem is a post found by its text, described by a snippet of
+// where the filter term occurs instead of its usual description.
+type searchResultItem struct {
+	PostMetadata
+	snippet string
+	matches int
+}
+
+func (r searchResultItem) Description() string {
+	if r.matches > 1 {
+		return fmt.Sprintf("%d matches · %s", r.matches, r.snippet)
+	}
+	return r.snippet
+}
+
+// asSearchResult describes item by the filter term's place in its text, if
+// the list is filtered and the term occurs there.
+func asSearchResult(item list.Item, m list.Model) list.Item {
+	p, ok := item.(PostMetadata)
+	if !ok || m.FilterState() == list.Unfiltered {
+		return item
+	}
+	snippet, matches := searchSnippet(p.PlainText, m.FilterValue())
+	if matches == 0 {
+		return item
+	}
+	return searchResultItem{PostMetadata: p, snippet: snippet, matches: matches}
+}
+
+// filterDebounce is how long typing in the post list's filter must pause
+// before it filters, so posts' text isn't scanned again for every keystroke.
+const filterDebounce = 150 * time.Millisecond
+
+// filterDebounceMsg says typing paused long enough for the filter numbered
+// seq to run.
+type filterDebounceMsg struct{ seq int }
+
+// debounceFilter holds back cmd, what the list returned for a key typed into
+// its filter, until typing pauses; a later key supersedes it. Accepting the
+// filter runs the latest held back at once, cancelling it drops it.
+func (m *model) debounceFilter(cmd tea.Cmd) tea.Cmd {
+	if !m.postList.SettingFilter() {
+		pending := m.pendingFilter
+		m.pendingFilter = nil
+		m.filterSeq++
+		if m.postList.FilterState() == list.FilterApplied {
+			return tea.Batch(cmd, pending)
+		}
+		return cmd
+	}
+	if cmd == nil {
+		return nil
+	}
+	m.pendingFilter = cmd
+	m.filterSeq++
+	seq := m.filterSeq
+	return tea.Tick(filterDebounce, func(time.Time) tea.Msg {
+		return filterDebounceMsg{seq: seq}
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name, markdown, want string
+	}{
+		{"headings and emphasis", "# Launch **day**\n\nA *very* `big` ~~small~~ rocket.", "Launch day A very big small rocket."},
+		{"links and images", "See [the pad](https://example.com) and ![a plume](plume.png).", "See the pad and a plume."},
+		{"lists and quotes", "- one\n* two\n1. three\n> quoted", "one two three quoted"},
+		{"code fences", "Before\n\n```go\nfmt.Println()\n```\n\nAfter", "Before fmt.Println() After"},
+		{"MDX", "<Callout type=\"warn\">Heads up</Callout>\n\nText", "Heads up Text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plainText(tt.markdown); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	long := strings.Repeat("filler words ", 10) + "the Falcon landed " + strings.Repeat("more words ", 10)
+	tests := []struct {
+		name, text, term string
+		want             string
+		wantMatches      int
+	}{
+		{"no term", "a rocket", "", "", 0},
+		{"no match", "a rocket", "boat", "", 0},
+		{"whole text", "a rocket launch", "rocket", "a " + boldOn + "rocket" + boldOff + " launch", 1},
+		{"ignores case", "Rocket, rocket, ROCKET", "rocket", boldOn + "Rocket" + boldOff + ", rocket, ROCKET", 3},
+		{
+			"cut on word boundaries", long, "falcon",
+			"…words filler words the " + boldOn + "Falcon" + boldOff + " landed more words more words more words more words more…",
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matches := searchSnippet(tt.text, tt.term)
+			if got != tt.want || matches != tt.wantMatches {
+				t.Errorf("got %q, %d matches; want %q, %d", got, matches, tt.want, tt.wantMatches)
+			}
+		})
+	}
+}
+
+func TestSearchSnippetOfPost(t *testing.T) {
+	post, err := parsePost("post.mdx", []byte("---\ntitle: Launch\nslug: launch\n---\n# Liftoff\n\nThe **Falcon** flew, see [the Falcon page](https://example.com/falcon).\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, matches := searchSnippet(post.PlainText, "falcon")
+	want := "Liftoff The " + boldOn + "Falcon" + boldOff + " flew, see the Falcon page."
+	if got != want || matches != 2 {
+		t.Errorf("got %q, %d matches; want %q, 2", got, matches, want)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Persisted user settings ---
+
+// settings are user preferences remembered between local runs. SSH sessions
+// start from the defaults and don't persist, since the server's config
+// directory is shared by every visitor.
+type settings struct {
+	GlamourStyle  string      `json:"glamourStyle,omitempty"`
+	ReadingSize   readingSize `json:"readingSize,omitempty"`
+	FirstRunDone  bool        `json:"firstRunDone,omitempty"` // The first-run prompt has been answered
+	HideStatusBar bool        `json:"hideStatusBar,omitempty"`
+}
+
+// settingsPath returns where settings are stored, under the user's config directory.
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bbs", "settings.json"), nil
+}
+
+// loadSettings reads saved settings, falling back to defaults when there are none.
+func loadSettings() settings {
+	var s settings
+	path, err := settingsPath()
+	if err != nil {
+		log.Printf("Error locating settings: %v", err)
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Error reading settings %s: %v", path, err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Printf("Error parsing settings %s: %v", path, err)
+		return settings{}
+	}
+	return s
+}
+
+// saveSettingsCmd writes settings in the background; failures are only logged.
+func saveSettingsCmd(s settings) tea.Cmd {
+	return func() tea.Msg {
+		path, err := settingsPath()
+		if err != nil {
+			log.Printf("Error locating settings: %v", err)
+			return nil
+		}
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			log.Printf("Error encoding settings: %v", err)
+			return nil
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			log.Printf("Error writing settings %s: %v", path, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Loading skeleton for the list screen ---
+
+type skeletonTickMsg time.Time
+
+// skeletonTick drives the shimmer that sweeps down the placeholder rows.
+func skeletonTick() tea.Cmd {
+	return tea.Tick(time.Millisecond*150, func(t time.Time) tea.Msg {
+		return skeletonTickMsg(t)
+	})
+}
+
+// renderSkeleton draws placeholder list rows shaped like the real list (title,
+// description, gap) filling the given size. When animate is set, the row at
+// frame is drawn brighter to give a shimmer as frame advances.
+func renderSkeleton(width, height, frame int, animate bool) string {
+	dim := lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "#E0E0E0", Dark: "#303030"})
+	faint := lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "#EEEEEE", Dark: "#222222"})
+	shine := lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "#CFCFCF", Dark: "#454545"})
+
+	bar := func(style lipgloss.Style, w int) string {
+		if w < 1 {
+			w = 1
+		}
+		return "  " + style.Render(strings.Repeat(" ", w))
+	}
+
+	lines := []string{"", bar(dim, min(12, width-4)), ""}
+	rows := (height - len(lines)) / 3
+	for i := 0; i < rows; i++ {
+		titleStyle, descStyle := dim, faint
+		if animate && i == frame%rows {
+			titleStyle, descStyle = shine, shine
+		}
+		// Vary the bar lengths a little so the rows read as separate items
+		titleWidth := (width - 4) * (45 + (i*17)%30) / 100
+		descWidth := (width - 4) * (30 + (i*23)%25) / 100
+		lines = append(lines, bar(titleStyle, titleWidth), bar(descStyle, descWidth), "")
+	}
+	return strings.Join(lines, "\n")
+}
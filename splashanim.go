@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Splash screen animation ---
+
+// splashAnim is the decorative element drawn under the splash message. It
+// advances with the splash tick, which only runs while the splash is showing.
+type splashAnim int
+
+const (
+	splashAnimNone splashAnim = iota
+	splashAnimMarquee
+	splashAnimStars
+)
+
+func (a splashAnim) String() string {
+	switch a {
+	case splashAnimMarquee:
+		return "marquee"
+	case splashAnimStars:
+		return "stars"
+	default:
+		return "none"
+	}
+}
+
+// parseSplashAnim parses a --splash-anim value.
+func parseSplashAnim(s string) (splashAnim, error) {
+	for a := splashAnimNone; a <= splashAnimStars; a++ {
+		if s == a.String() {
+			return a, nil
+		}
+	}
+	return splashAnimNone, fmt.Errorf("unknown splash animation %q (want none, marquee or stars)", s)
+}
+
+const (
+	marqueeText = " ★ Space Coast Devs BBS ★ ssh in, read on ★ posts, code and launch-day chatter from the Space Coast"
+	starsHeight = 5
+)
+
+// render draws frame of the animation at most width cells wide, or returns ""
+// for none.
+func (a splashAnim) render(frame, width int) string {
+	width = min(width, 60)
+	if width < 1 {
+		return ""
+	}
+	dimmed := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+
+	switch a {
+	case splashAnimMarquee:
+		text := []rune(marqueeText)
+		line := make([]rune, width)
+		for i := range line {
+			line[i] = text[(frame+i)%len(text)]
+		}
+		return dimmed.Render(string(line))
+
+	case splashAnimStars:
+		// Stars sit at fixed points of an endless field that drifts left a
+		// column per frame, every other row twice as fast for some parallax.
+		rows := make([]string, starsHeight)
+		for y := range rows {
+			speed := 1 + y%2
+			var row strings.Builder
+			for x := 0; x < width; x++ {
+				switch h := starHash(x+frame*speed, y); {
+				case h%29 == 0:
+					row.WriteRune('✦')
+				case h%11 == 0:
+					row.WriteRune('·')
+				default:
+					row.WriteRune(' ')
+				}
+			}
+			rows[y] = row.String()
+		}
+		return dimmed.Render(strings.Join(rows, "\n"))
+	}
+	return ""
+}
+
+// starHash scatters stars over the field without any state to keep between frames.
+func starHash(x, y int) uint32 {
+	h := uint32(x)*2654435761 ^ uint32(y)*2246822519
+	h ^= h >> 15
+	return h * 2654435761 >> 7
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/keygen"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// --- SSH server address, host keys and algorithms ---
+
+// The default algorithms are the modern subset of what x/crypto implements:
+// no SHA-1 key exchange or MACs, and only AEAD or CTR ciphers.
+const (
+	defaultHostKeyTypes = "ed25519"
+	defaultKeyExchanges = "curve25519-sha256,curve25519-sha256@libssh.org,ecdh-sha2-nistp256,ecdh-sha2-nistp384,ecdh-sha2-nistp521,diffie-hellman-group16-sha512,diffie-hellman-group14-sha256"
+	defaultCiphers      = "chacha20-poly1305@openssh.com,aes256-gcm@openssh.com,aes128-gcm@openssh.com,aes256-ctr,aes192-ctr,aes128-ctr"
+	defaultMACs         = "hmac-sha2-256-etm@openssh.com,hmac-sha2-512-etm@openssh.com,hmac-sha2-256,hmac-sha2-512"
+)
+
+// defaultSSHAddr is where the SSH server listens unless --ssh-addr says
+// otherwise: on every interface, at $PORT when the environment sets it.
+func defaultSSHAddr() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":23234"
+}
+
+// splitList splits a comma-separated flag value, ignoring spaces and empty entries.
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// hostKeyOptions loads a host key for each of the comma-separated types
+// (ed25519, ecdsa or rsa), generating any that don't exist yet along with
+// their directory. The ed25519 key is at keyPath; the others are
+// ssh_host_<type> beside it.
+func hostKeyOptions(types, keyPath string) ([]ssh.Option, error) {
+	var opts []ssh.Option
+	for _, t := range splitList(types) {
+		keyType := keygen.KeyType(t)
+		if keyType != keygen.Ed25519 && keyType != keygen.ECDSA && keyType != keygen.RSA {
+			return nil, fmt.Errorf("unknown host key type %q (want ed25519, ecdsa or rsa)", t)
+		}
+
+		path := filepath.Join(filepath.Dir(keyPath), "ssh_host_"+t)
+		if keyType == keygen.Ed25519 {
+			path = keyPath
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				return nil, fmt.Errorf("creating host key directory: %w", err)
+			}
+			if _, err := keygen.New(path, keygen.WithKeyType(keyType), keygen.WithWrite()); err != nil {
+				return nil, fmt.Errorf("generating %s host key: %w", t, err)
+			}
+			log.Printf("Generated %s host key %s", t, path)
+		}
+		opts = append(opts, ssh.HostKeyFile(path))
+	}
+	if len(opts) == 0 {
+		return nil, fmt.Errorf("no host key types given")
+	}
+	return opts, nil
+}
+
+// authOptions let every reader in. A reader's key is asked for first so their
+// stats can be kept by its fingerprint; readers without one get in through
+// keyboard-interactive auth, with no questions asked.
+func authOptions() []ssh.Option {
+	return []ssh.Option{
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
+	}
+}
+
+// algorithmsOption limits the server to the comma-separated key exchanges,
+// ciphers and MACs, in preference order. Names x/crypto doesn't implement are
+// an error rather than being skipped silently.
+func algorithmsOption(kex, ciphers, macs string) (ssh.Option, error) {
+	config := gossh.Config{KeyExchanges: splitList(kex), Ciphers: splitList(ciphers), MACs: splitList(macs)}
+	requested := config
+	config.SetDefaults() // drops unsupported names, which is how they're found
+
+	for _, list := range []struct {
+		name                 string
+		requested, supported []string
+	}{
+		{"key exchange", requested.KeyExchanges, config.KeyExchanges},
+		{"cipher", requested.Ciphers, config.Ciphers},
+		{"MAC", requested.MACs, config.MACs},
+	} {
+		if len(list.requested) == 0 {
+			return nil, fmt.Errorf("no %s algorithms given", list.name)
+		}
+		for _, name := range list.requested {
+			if !slices.Contains(list.supported, name) {
+				return nil, fmt.Errorf("unsupported %s algorithm %q", list.name, name)
+			}
+		}
+	}
+
+	return func(s *ssh.Server) error {
+		s.ServerConfigCallback = func(ssh.Context) *gossh.ServerConfig {
+			return &gossh.ServerConfig{Config: config}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	ssh "github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// --- Reading statistics ---
+
+// localStatsUser is who the stats of a local run belong to.
+const localStatsUser = "local"
+
+// favoritesShown is how many favorite categories and tags the stats screen lists.
+const favoritesShown = 3
+
+// statsKeptFor is how long a reader's stats are kept after their last visit,
+// and maxStatsReaders how many readers' are kept at most, the latest visitors'.
+const (
+	statsKeptFor    = 365 * 24 * time.Hour
+	maxStatsReaders = 10000
+)
+
+// readingStats is one reader's history: what they opened, and how many days
+// in a row they've visited.
+type readingStats struct {
+	Reads     map[string]postRead `json:"reads,omitempty"`     // By post key
+	LastVisit string              `json:"lastVisit,omitempty"` // Day of the latest visit, as 2006-01-02
+	Streak    int                 `json:"streak,omitempty"`    // Days visited in a row up to LastVisit
+}
+
+// postRead is how often a post was opened, with what the stats screen
+// needs to know about it even once it's gone from the blog.
+type postRead struct {
+	Times    int      `json:"times"`
+	Minutes  int      `json:"minutes"`
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// recordVisit counts now's day towards the streak, which starts over after
+// a day without a visit.
+func (s *readingStats) recordVisit(now time.Time) {
+	today := now.Format(time.DateOnly)
+	switch s.LastVisit {
+	case today:
+		return
+	case now.AddDate(0, 0, -1).Format(time.DateOnly):
+		s.Streak++
+	default:
+		s.Streak = 1
+	}
+	s.LastVisit = today
+}
+
+// recordRead counts an opening of p.
+func (s *readingStats) recordRead(p PostMetadata) {
+	if s.Reads == nil {
+		s.Reads = make(map[string]postRead)
+	}
+	read := s.Reads[p.key()]
+	read.Times++
+	read.Minutes, read.Category, read.Tags = p.ReadingMinutes, p.Category, p.Tags
+	s.Reads[p.key()] = read
+}
+
+// loadStats starts the model on user's saved stats, counting today's visit,
+// which is saved with the first post they read. With no user the stats start
+// empty and last the session. The post list marks the posts not read yet.
+func (m *model) loadStats(user string) {
+	m.statsUser = user
+	m.stats = readingStats{}
+	if user != "" {
+		m.stats = loadStats(user)
+	}
+	m.stats.recordVisit(time.Now())
+	if m.stats.Reads == nil {
+		m.stats.Reads = make(map[string]postRead)
+	}
+	// The delegate shares the map, so posts recordRead adds lose their mark
+	m.listDelegate.reads = m.stats.Reads
+	m.postList.SetDelegate(m.listDelegate)
+}
+
+// recordRead counts an opening of p, saving the stats if they have a user.
+func (m *model) recordRead(p PostMetadata) tea.Cmd {
+	m.stats.recordRead(p)
+	if m.statsUser == "" {
+		return nil
+	}
+	return saveStatsCmd(m.statsUser, m.stats)
+}
+
+// unreadMark goes before an item on the post list: a dot if it's a post
+// missing from reads, or else a blank as wide.
+func unreadMark(reads map[string]postRead, item list.Item) string {
+	p, ok := item.(interface{ key() string }) // PostMetadata, or one as a search result
+	if !ok {
+		return "  "
+	}
+	if _, read := reads[p.key()]; read {
+		return "  "
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render("●") + " "
+}
+
+// statsSummary is what the stats screen shows.
+type statsSummary struct {
+	postsRead  int
+	minutes    int      // Estimated reading time of the posts read, each counted once
+	categories []string // Most read first
+	tags       []string
+	streak     int // 0 once a day has passed without a visit
+}
+
+func (s readingStats) summary(now time.Time) statsSummary {
+	sum := statsSummary{postsRead: len(s.Reads)}
+	categories := make(map[string]int)
+	tags := make(map[string]int)
+	for _, read := range s.Reads {
+		sum.minutes += read.Minutes
+		if read.Category != "" {
+			categories[read.Category] += read.Times
+		}
+		for _, tag := range read.Tags {
+			tags[tag] += read.Times
+		}
+	}
+	sum.categories = mostCounted(categories, favoritesShown)
+	sum.tags = mostCounted(tags, favoritesShown)
+	if s.LastVisit == now.Format(time.DateOnly) || s.LastVisit == now.AddDate(0, 0, -1).Format(time.DateOnly) {
+		sum.streak = s.Streak
+	}
+	return sum
+}
+
+// mostCounted returns up to n names with the highest counts, ties in
+// alphabetical order.
+func mostCounted(counts map[string]int, n int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names[:min(n, len(names))]
+}
+
+// view lays the summary out for the stats screen.
+func (sum statsSummary) view() string {
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}).Width(20)
+	list := func(names []string) string {
+		if len(names) == 0 {
+			return "none yet"
+		}
+		return strings.Join(names, ", ")
+	}
+	days := "days"
+	if sum.streak == 1 {
+		days = "day"
+	}
+	lines := []string{
+		labelStyle.Render("Posts read") + fmt.Sprint(sum.postsRead),
+		labelStyle.Render("Reading time") + formatMinutes(sum.minutes),
+		labelStyle.Render("Top categories") + list(sum.categories),
+		labelStyle.Render("Top tags") + list(sum.tags),
+		labelStyle.Render("Visit streak") + fmt.Sprintf("%d %s", sum.streak, days),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatMinutes is a reading time such as "45 min" or "3 h 20 min".
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%d h %d min", minutes/60, minutes%60)
+}
+
+// sessionStatsUser is who an SSH session's stats are kept for: the
+// fingerprint of the key it authenticated with. Sessions without a key get
+// "", so their stats aren't saved; a user name is anyone's to claim, and
+// would let every made-up one add to the file.
+func sessionStatsUser(sess ssh.Session) string {
+	if key := sess.PublicKey(); key != nil {
+		return gossh.FingerprintSHA256(key)
+	}
+	return ""
+}
+
+// statsPath returns where every reader's stats are stored, under the user's
+// config directory next to the settings.
+func statsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bbs", "stats.json"), nil
+}
+
+// loadStats reads user's stats, or none when nothing was saved yet.
+func loadStats(user string) readingStats {
+	var s readingStats
+	all, err := readAllStats()
+	if err != nil {
+		log.Printf("Error reading stats: %v", err)
+		return s
+	}
+	if raw, ok := all[user]; ok {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			log.Printf("Error parsing stats for %s: %v", user, err)
+		}
+	}
+	return s
+}
+
+// readAllStats reads the stats file, keeping each reader's stats encoded.
+// Saves replace the file whole, so it needs no lock.
+func readAllStats() (map[string]json.RawMessage, error) {
+	all := make(map[string]json.RawMessage)
+	path, err := statsPath()
+	if err != nil {
+		return all, err
+	}
+	return all, readStatsFile(path, all)
+}
+
+func readStatsFile(path string, all map[string]json.RawMessage) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return nil
+}
+
+// saveStatsCmd writes user's stats in the background, leaving other readers'
+// as they are; failures are only logged. s is encoded right away, as the
+// model goes on changing it.
+func saveStatsCmd(user string, s readingStats) tea.Cmd {
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		log.Printf("Error encoding stats: %v", err)
+		return nil
+	}
+	return func() tea.Msg {
+		path, err := statsPath()
+		if err != nil {
+			log.Printf("Error locating stats: %v", err)
+			return nil
+		}
+		// Other sessions' saves in between would be lost without the lock
+		err = withFileLock(path, func() error {
+			all := make(map[string]json.RawMessage)
+			if err := readStatsFile(path, all); err != nil {
+				return err // Don't overwrite a file that couldn't be read
+			}
+			all[user] = encoded
+			pruneStats(all, time.Now())
+			data, err := json.Marshal(all)
+			if err != nil {
+				return err
+			}
+			return writeFileAtomic(path, data)
+		})
+		if err != nil {
+			log.Printf("Error saving stats: %v", err)
+		}
+		return nil
+	}
+}
+
+// pruneStats drops the stats of readers who haven't visited for statsKeptFor,
+// and then of the earliest visitors beyond maxStatsReaders.
+func pruneStats(all map[string]json.RawMessage, now time.Time) {
+	cutoff := now.Add(-statsKeptFor).Format(time.DateOnly)
+	lastVisits := make(map[string]string, len(all))
+	for user, raw := range all {
+		var s readingStats
+		json.Unmarshal(raw, &s) // One that won't decode has no visit, and goes first
+		if s.LastVisit < cutoff {
+			delete(all, user)
+			continue
+		}
+		lastVisits[user] = s.LastVisit
+	}
+	if len(all) <= maxStatsReaders {
+		return
+	}
+	users := make([]string, 0, len(all))
+	for user := range all {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if lastVisits[users[i]] != lastVisits[users[j]] {
+			return lastVisits[users[i]] < lastVisits[users[j]]
+		}
+		return users[i] < users[j]
+	})
+	for _, user := range users[:len(users)-maxStatsReaders] {
+		delete(all, user)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	ssh "github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestRecordVisit(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		stats      readingStats
+		wantStreak int
+	}{
+		{"first visit", readingStats{}, 1},
+		{"again today", readingStats{LastVisit: "2024-03-01", Streak: 4}, 4},
+		{"yesterday, across a leap day", readingStats{LastVisit: "2024-02-29", Streak: 4}, 5},
+		{"missed a day", readingStats{LastVisit: "2024-02-28", Streak: 4}, 1},
+		{"last year", readingStats{LastVisit: "2023-03-01", Streak: 30}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.stats
+			s.recordVisit(now)
+			if s.Streak != tt.wantStreak || s.LastVisit != "2024-03-01" {
+				t.Errorf("got streak %d on %s, want %d on 2024-03-01", s.Streak, s.LastVisit, tt.wantStreak)
+			}
+		})
+	}
+}
+
+func TestRecordRead(t *testing.T) {
+	var s readingStats
+	post := PostMetadata{PostTitle: "Launch", Slug: "launch", ReadingMinutes: 4, Category: "Space", Tags: []string{"rockets"}}
+	s.recordRead(post)
+	s.recordRead(post)
+	unslugged := PostMetadata{PostTitle: "No slug", ReadingMinutes: 1}
+	s.recordRead(unslugged)
+
+	want := map[string]postRead{
+		"launch":        {Times: 2, Minutes: 4, Category: "Space", Tags: []string{"rockets"}},
+		unslugged.key(): {Times: 1, Minutes: 1},
+	}
+	if !reflect.DeepEqual(s.Reads, want) {
+		t.Errorf("got %+v, want %+v", s.Reads, want)
+	}
+
+	// A post edited since keeps its count with the latest details
+	post.ReadingMinutes, post.Tags = 6, []string{"rockets", "florida"}
+	s.recordRead(post)
+	if got := s.Reads["launch"]; got.Times != 3 || got.Minutes != 6 || len(got.Tags) != 2 {
+		t.Errorf("after an edit got %+v", got)
+	}
+}
+
+func TestStatsSummary(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	reads := map[string]postRead{
+		"a": {Times: 3, Minutes: 5, Category: "Space", Tags: []string{"rockets", "florida"}},
+		"b": {Times: 1, Minutes: 10, Category: "Go", Tags: []string{"florida"}},
+		"c": {Times: 1, Minutes: 50, Category: "Go"},
+		"d": {Times: 1, Minutes: 2, Tags: []string{"meetups"}},
+	}
+	tests := []struct {
+		name  string
+		stats readingStats
+		want  statsSummary
+	}{
+		{"nothing read", readingStats{}, statsSummary{categories: []string{}, tags: []string{}}},
+		{
+			"visited today",
+			readingStats{Reads: reads, LastVisit: "2024-03-01", Streak: 3},
+			// Space was read three times, Go twice; florida four times, rockets three
+			statsSummary{postsRead: 4, minutes: 67, categories: []string{"Space", "Go"}, tags: []string{"florida", "rockets", "meetups"}, streak: 3},
+		},
+		{"streak kept till the end of the next day", readingStats{LastVisit: "2024-02-29", Streak: 3}, statsSummary{categories: []string{}, tags: []string{}, streak: 3}},
+		{"streak over", readingStats{LastVisit: "2024-02-28", Streak: 3}, statsSummary{categories: []string{}, tags: []string{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.summary(now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostCounted(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[string]int
+		n      int
+		want   []string
+	}{
+		{"empty", nil, 3, []string{}},
+		{"fewer than n", map[string]int{"b": 1, "a": 2}, 3, []string{"a", "b"}},
+		{"cut to n", map[string]int{"a": 1, "b": 5, "c": 3, "d": 4}, 2, []string{"b", "d"}},
+		{"ties alphabetical", map[string]int{"go": 2, "c": 2, "zig": 2, "rust": 1}, 3, []string{"c", "go", "zig"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mostCounted(tt.counts, tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneStats(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	visited := func(day string) json.RawMessage {
+		return json.RawMessage(fmt.Sprintf(`{"lastVisit": %q, "streak": 1}`, day))
+	}
+	all := map[string]json.RawMessage{
+		"today":       visited("2024-03-01"),
+		"within year": visited("2023-03-02"),
+		"stale":       visited("2023-02-01"),
+		"never":       json.RawMessage(`{}`),
+		"garbled":     json.RawMessage(`"what"`),
+	}
+	pruneStats(all, now)
+	var kept []string
+	for user := range all {
+		kept = append(kept, user)
+	}
+	slices.Sort(kept)
+	if want := []string{"today", "within year"}; !slices.Equal(kept, want) {
+		t.Errorf("kept %q, want %q", kept, want)
+	}
+
+	// Past the cap the earliest visitors go
+	all = make(map[string]json.RawMessage)
+	for i := range maxStatsReaders {
+		all[fmt.Sprintf("reader-%05d", i)] = visited("2024-02-01")
+	}
+	all["earliest"] = visited("2024-01-01")
+	all["latest"] = visited("2024-03-01")
+	pruneStats(all, now)
+	if len(all) != maxStatsReaders {
+		t.Errorf("kept %d readers, want %d", len(all), maxStatsReaders)
+	}
+	if _, ok := all["earliest"]; ok {
+		t.Error("kept the earliest visitor")
+	}
+	if _, ok := all["latest"]; !ok {
+		t.Error("dropped the latest visitor")
+	}
+}
+
+// keySession is an SSH session that authenticated with key, or without one when it's nil.
+type keySession struct {
+	ssh.Session
+	key ssh.PublicKey
+}
+
+func (s keySession) PublicKey() ssh.PublicKey { return s.key }
+func (s keySession) User() string             { return "guest" }
+
+func TestSessionStatsUser(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sessionStatsUser(keySession{key: key}); !strings.HasPrefix(got, "SHA256:") {
+		t.Errorf("key session's user is %q, want its fingerprint", got)
+	}
+	if got := sessionStatsUser(keySession{}); got != "" {
+		t.Errorf("keyless session's user is %q, want none", got)
+	}
+
+	// Over the server, readers with a key are known by it and those without
+	// still get in
+	users := make(chan string, 1)
+	server, err := wish.NewServer(append(authOptions(),
+		wish.WithHostKeyPath(filepath.Join(t.TempDir(), "host_key")),
+		wish.WithMiddleware(func(ssh.Handler) ssh.Handler {
+			return func(sess ssh.Session) { users <- sessionStatsUser(sess) }
+		}),
+	)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(ln)
+	defer server.Close()
+
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noQuestions := func(string, string, []string, []bool) ([]string, error) { return nil, nil }
+	for _, tt := range []struct {
+		name string
+		auth gossh.AuthMethod
+		want string
+	}{
+		{"key", gossh.PublicKeys(signer), gossh.FingerprintSHA256(signer.PublicKey())},
+		{"no key", gossh.KeyboardInteractive(noQuestions), ""},
+	} {
+		client, err := gossh.Dial("tcp", ln.Addr().String(), &gossh.ClientConfig{
+			User:            "guest",
+			Auth:            []gossh.AuthMethod{tt.auth},
+			HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		sess, err := client.NewSession()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		sess.Run("")
+		if got := <-users; got != tt.want {
+			t.Errorf("%s session's user is %q, want %q", tt.name, got, tt.want)
+		}
+		client.Close()
+	}
+
+	// Without a user the session's reads count but aren't saved
+	m := testModel(t)
+	m.loadStats("")
+	if cmd := m.recordRead(PostMetadata{PostTitle: "Post", Slug: "post"}); cmd != nil {
+		t.Error("reading without a stats user saves")
+	}
+	if m.stats.Reads["post"].Times != 1 || m.stats.Streak != 1 {
+		t.Errorf("session stats are %+v", m.stats)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Streaming long posts ---
+
+// streamThreshold is the size of markdown past which a post is rendered a
+// chunk at a time, so the reader sees its start while glamour works through
+// the rest.
+const streamThreshold = 32 << 10
+
+// streamChunkSize is about how much markdown goes in a chunk.
+const streamChunkSize = 8 << 10
+
+// headingRe matches an ATX heading line.
+var headingRe = regexp.MustCompile(`^ {0,3}#{1,6}(\s|$)`)
+
+// renderStream is a post being rendered chunk by chunk in the background.
+type renderStream struct {
+	key      string // The render cache key the full output goes under
+	style    string
+	wrap     int
+	rendered string   // Output of the chunks done so far
+	chunks   []string // Markdown left to render
+	inFlight bool     // Whether the next chunk is rendering
+}
+
+// chunkRenderedMsg is the output of the next chunk of a stream.
+type chunkRenderedMsg struct {
+	key string
+	out string
+	err error
+}
+
+// renderMarkdown renders markdown with glamour in the given style and wrap.
+func renderMarkdown(style string, wrap int, markdown string) (string, error) {
+	renderer, err := glamour.NewTermRenderer(
+		glamourStyleOption(style),
+		glamour.WithWordWrap(wrap),
+	)
+	if err != nil {
+		return "", fmt.Errorf("creating glamour renderer: %w", err)
+	}
+	out, err := renderer.Render(markdown)
+	if err != nil {
+		return "", err
+	}
+	return trimTrailingBlankLines(out), nil
+}
+
+// splitSections cuts markdown into chunks of at least size bytes, each
+// starting at a heading outside code blocks, so every chunk renders as it
+// would in the whole. A post without headings stays one chunk.
+func splitSections(markdown string, size int) []string {
+	var chunks []string
+	var chunk strings.Builder
+	fence := ""
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		if fence != "" {
+			if isFenceCloser(line, fence) {
+				fence = ""
+			}
+		} else if f, _, ok := fenceOpener(line); ok {
+			fence = f
+		} else if headingRe.MatchString(line) && chunk.Len() >= size {
+			chunks = append(chunks, chunk.String())
+			chunk.Reset()
+		}
+		chunk.WriteString(line)
+	}
+	return append(chunks, chunk.String())
+}
+
+// startStream renders the first chunk of markdown now and leaves the rest to
+// the stream, returning what to show meanwhile.
+func (m *model) startStream(key, style string, wrap int, markdown string) (string, error) {
+	chunks := splitSections(markdown, streamChunkSize)
+	first, err := renderMarkdown(style, wrap, chunks[0])
+	if err != nil {
+		return "", err
+	}
+	m.stream = &renderStream{key: key, style: style, wrap: wrap, rendered: first, chunks: chunks[1:]}
+	return m.stream.view(), nil
+}
+
+// view is the output so far, marked as unfinished.
+func (s *renderStream) view() string {
+	more := lipgloss.NewStyle().Padding(0, 2).Faint(true).Render("loading more…")
+	return s.rendered + "\n\n" + more
+}
+
+// nextChunkCmd renders the stream's next chunk, unless one is under way or
+// nothing is streaming.
+func (m model) nextChunkCmd() tea.Cmd {
+	s := m.stream
+	if s == nil || s.inFlight || len(s.chunks) == 0 {
+		return nil
+	}
+	s.inFlight = true
+	key, style, wrap, chunk := s.key, s.style, s.wrap, s.chunks[0]
+	return func() tea.Msg {
+		out, err := renderMarkdown(style, wrap, chunk)
+		return chunkRenderedMsg{key: key, out: out, err: err}
+	}
+}
+
+// appendChunk adds a rendered chunk to the stream it belongs to, caching the
+// whole once the last one is in.
+func (m *model) appendChunk(msg chunkRenderedMsg) {
+	s := m.stream
+	if s == nil || s.key != msg.key {
+		return // The reader moved on to another post or rendering
+	}
+	if msg.err != nil {
+		m.stream = nil
+		m.viewport.SetContent("Error rendering content.")
+		return
+	}
+	s.rendered += "\n" + msg.out
+	s.chunks = s.chunks[1:]
+	s.inFlight = false
+	if len(s.chunks) == 0 {
+		m.renderCache[s.key] = s.rendered
+		m.stream = nil
+	}
+	offset := m.viewport.YOffset
+	m.setViewportContent()
+	if !m.follow {
+		m.viewport.SetYOffset(offset)
+	}
+}
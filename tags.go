@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Browsing by tag ---
+
+// tagItem is a tag in the tag menu, with how many posts carry it.
+type tagItem struct {
+	name  string
+	count int
+}
+
+func (t tagItem) Title() string { return t.name }
+
+func (t tagItem) Description() string {
+	if t.count == 1 {
+		return "1 post"
+	}
+	return fmt.Sprintf("%d posts", t.count)
+}
+
+func (t tagItem) FilterValue() string { return t.name }
+
+// tagItems lists the distinct tags of posts, most used first.
+func tagItems(posts []PostMetadata) []list.Item {
+	counts := make(map[string]int)
+	for _, p := range posts {
+		for _, tag := range p.Tags {
+			counts[tag]++
+		}
+	}
+	names := mostCounted(counts, len(counts))
+	items := make([]list.Item, len(names))
+	for i, name := range names {
+		items[i] = tagItem{name: name, count: counts[name]}
+	}
+	return items
+}
+
+// newTagList is the tag menu, drawn like the post list.
+func newTagList(delegate list.DefaultDelegate) list.Model {
+	l := list.New([]list.Item{}, delegate, 0, 0)
+	l.Title = "Tags"
+	l.SetFilteringEnabled(true)
+	l.SetStatusBarItemName("tag", "tags")
+	l.KeyMap.Quit.SetHelp("q", "all posts") // See keyMap.TagsBack
+	l.Styles.Title = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	l.Styles.PaginationStyle = list.DefaultStyles().PaginationStyle.Foreground(lipgloss.Color("240"))
+	l.Styles.HelpStyle = list.DefaultStyles().HelpStyle.Foreground(lipgloss.Color("240"))
+	return l
+}
+
+// openTagMenu fills the tag menu from the loaded posts, with the cursor on
+// the tag the post list is limited to, if any.
+func (m *model) openTagMenu() {
+	items := tagItems(m.posts)
+	m.tagList.ResetFilter()
+	m.tagList.SetItems(items)
+	m.tagList.Select(0)
+	for i, item := range items {
+		if item.(tagItem).name == m.tagFilter {
+			m.tagList.Select(i)
+		}
+	}
+	m.currentScreen = tagsScreen
+}
+
+// filterByTag limits the post list to the posts tagged tag, or shows them
+// all again for "".
+func (m *model) filterByTag(tag string) {
+	m.tagFilter = tag
+	m.applySort()
+	m.postList.Select(0)
+}
+
+// matchesTag reports whether p is shown with the post list limited to tag.
+func matchesTag(p PostMetadata, tag string) bool {
+	return tag == "" || slices.Contains(p.Tags, tag)
+}
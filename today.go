@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- On this day ---
+
+// onThisDay returns the posts published on now's month and day in earlier
+// years, newest first as posts are. Posts from 29 February show up on 28
+// February in years without one, so they still come around every year.
+func onThisDay(posts []PostMetadata, now time.Time) []PostMetadata {
+	var matches []PostMetadata
+	for _, p := range posts {
+		if p.PublishDate.IsZero() || p.PublishDate.Year() >= now.Year() {
+			continue
+		}
+		if sameDayOfYear(p.PublishDate, now) {
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
+
+// sameDayOfYear reports whether published falls on now's month and day,
+// counting 29 February as 28 February when now's year isn't a leap year.
+func sameDayOfYear(published, now time.Time) bool {
+	month, day := published.Month(), published.Day()
+	if month == time.February && day == 29 && !isLeapYear(now.Year()) {
+		day = 28
+	}
+	return month == now.Month() && day == now.Day()
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// todayView is the state of the on this day screen.
+type todayView struct {
+	date   time.Time
+	posts  []PostMetadata
+	cursor int
+}
+
+func newTodayView(posts []PostMetadata, now time.Time) todayView {
+	return todayView{date: now, posts: onThisDay(posts, now)}
+}
+
+// move shifts the cursor by delta posts, staying on the list.
+func (t *todayView) move(delta int) {
+	t.cursor = max(0, min(t.cursor+delta, len(t.posts)-1))
+}
+
+// selected is the post under the cursor, if there are any.
+func (t todayView) selected() (PostMetadata, bool) {
+	if t.cursor >= len(t.posts) {
+		return PostMetadata{}, false
+	}
+	return t.posts[t.cursor], true
+}
+
+// view lists the posts with how many years ago each was published.
+func (t todayView) view(width int) string {
+	dimmed := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+	selected := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+
+	if len(t.posts) == 0 {
+		return fmt.Sprintf("Nothing was posted on %s %d in earlier years.\nCheck back tomorrow!", t.date.Month(), t.date.Day())
+	}
+	var lines []string
+	for i, p := range t.posts {
+		years := t.date.Year() - p.PublishDate.Year()
+		ago := "1 year ago"
+		if years > 1 {
+			ago = fmt.Sprintf("%d years ago", years)
+		}
+		text := fmt.Sprintf("%d  %s", p.PublishDate.Year(), p.Title())
+		if i == t.cursor {
+			text = selected.Render("▸ " + text)
+		} else {
+			text = "  " + text
+		}
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(width).Render(text+dimmed.Render(" · "+ago)))
+	}
+	return strings.Join(lines, "\n")
+}
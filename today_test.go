@@ -0,0 +1,43 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOnThisDay(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 9, 0, 0, 0, time.UTC) }
+	posts := []PostMetadata{
+		{PostTitle: "Today, this year", PublishDate: date(2025, time.March, 1)},
+		{PostTitle: "Leap day", PublishDate: date(2024, time.February, 29)},
+		{PostTitle: "A year ago", PublishDate: date(2024, time.March, 1)},
+		{PostTitle: "28 February", PublishDate: date(2023, time.February, 28)},
+		{PostTitle: "Two years ago", PublishDate: date(2023, time.March, 1)},
+		{PostTitle: "Old leap day", PublishDate: date(2020, time.February, 29)},
+		{PostTitle: "Undated"},
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{"matches earlier years only", date(2025, time.March, 1), []string{"A year ago", "Two years ago"}},
+		{"leap days on 28 February of other years", date(2025, time.February, 28), []string{"Leap day", "28 February", "Old leap day"}},
+		{"leap days on their own day in leap years", date(2028, time.February, 29), []string{"Leap day", "Old leap day"}},
+		{"not on 28 February of leap years", date(2028, time.February, 28), []string{"28 February"}},
+		{"not the next day", date(2025, time.March, 2), nil},
+		{"nothing", date(2025, time.July, 4), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := titles(onThisDay(posts, tt.now)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if view := newTodayView(posts, date(2025, time.July, 4)).view(80); view != "Nothing was posted on July 4 in earlier years.\nCheck back tomorrow!" {
+		t.Errorf("empty view is %q", view)
+	}
+}
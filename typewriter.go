@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- Typewriter splash ---
+
+type typeTickMsg time.Time
+
+// typeTickInterval is how often more of the splash is typed out; the number of
+// characters per tick follows from --typewriter.
+const typeTickInterval = 30 * time.Millisecond
+
+func typeTick() tea.Cmd {
+	return tea.Tick(typeTickInterval, func(t time.Time) tea.Msg {
+		return typeTickMsg(t)
+	})
+}
+
+// typeOut shows the first n visible characters of s, keeping their styling,
+// and blanks the rest so the text keeps its shape and doesn't shift about as
+// it's centered. It also returns how many of the n are left once s is shown.
+func typeOut(s string, n int) (string, int) {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		w := ansi.StringWidth(line)
+		switch {
+		case n >= w:
+			n -= w
+		case n > 0:
+			lines[i] = ansi.Truncate(line, n, "") + strings.Repeat(" ", w-n)
+			n = 0
+		default:
+			lines[i] = strings.Repeat(" ", w)
+		}
+	}
+	return strings.Join(lines, "\n"), n
+}
+
+// splashLength is how many characters the typewriter has to type.
+func (m model) splashLength() int {
+	return ansi.StringWidth(strings.ReplaceAll(m.opts.motd, "\n", "")) + ansi.StringWidth(m.splashMessage)
+}
+
+// typing reports whether the splash is still being typed out.
+func (m model) typing() bool {
+	return m.opts.typewriter > 0 && m.typed < m.splashLength()
+}
+
+// typeStep is how many characters each tick adds.
+func (m model) typeStep() int {
+	return max(1, int(float64(m.opts.typewriter)*typeTickInterval.Seconds()+0.5))
+}
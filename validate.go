@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// --- validate subcommand ---
+
+// runValidate parses each post file the way the fetcher does and reports the
+// result per file, so authors can check frontmatter before pushing. It returns
+// the process exit status: 1 if any file failed, 2 if none were given.
+func runValidate(paths []string, w io.Writer) int {
+	if len(paths) == 0 {
+		fmt.Fprintln(w, "usage: bbs validate <file.mdx>...")
+		return 2
+	}
+
+	status := 0
+	for _, path := range paths {
+		body, err := os.ReadFile(path)
+		if err == nil {
+			_, err = parsePost(path, body)
+		}
+		if err != nil {
+			fmt.Fprintln(w, err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(w, "%s: ok\n", path)
+	}
+	return status
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// --- warm subcommand ---
+
+// runWarm fetches the posts at once and then every interval, without a UI,
+// through the same fetch and cache as the TUI, so sessions started meanwhile
+// load from a fresh cache. It returns when ctx is done, such as on SIGTERM,
+// after finishing any refresh under way.
+func runWarm(ctx context.Context, opts options, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid --interval %s: must be positive", interval)
+	}
+	path, err := cachePath(opts.gistID, opts.repo)
+	if err != nil {
+		return fmt.Errorf("locating the post cache: %w", err)
+	}
+	opts.metadataOnly = false // Nothing is shown, so there are no bodies to drop
+	log.Printf("Warming %s every %s", path, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		warmCache(opts)
+		select {
+		case <-ctx.Done():
+			log.Printf("Stopped warming the cache")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// warmCache refreshes the post cache once and logs how it went.
+func warmCache(opts options) {
+	start := time.Now()
+	msg, ok := fetchCmd(opts, nil, nil)().(postsLoadedMsg)
+	switch {
+	case !ok:
+		log.Printf("Cache refresh failed: the fetch returned %T", msg)
+	case msg.err != nil:
+		log.Printf("Cache refresh failed after %s: %v", time.Since(start).Round(time.Millisecond), msg.err)
+	case !msg.cachedAt.IsZero():
+		log.Printf("Cache refresh failed after %s; keeping the posts cached at %s", time.Since(start).Round(time.Millisecond), msg.cachedAt.Format(time.RFC3339))
+	default:
+		log.Printf("Cache refreshed with %d posts in %s", len(msg.posts), time.Since(start).Round(time.Millisecond))
+	}
+}
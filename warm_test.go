@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRunWarm(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	f := compareFixture{files: map[string]string{"a.mdx": fixturePost("A", 1), "b.mdx": fixturePost("B", 2)}}
+	srv, repo := f.serve(t)
+	f.listing = []GitHubContent{
+		{Name: "a.mdx", Path: "posts/a.mdx", Type: "file", DownloadURL: srv.URL + "/raw/a.mdx"},
+		{Name: "b.mdx", Path: "posts/b.mdx", Type: "file", DownloadURL: srv.URL + "/raw/b.mdx"},
+	}
+	opts := options{repo: repo}
+	path, err := cachePath("", repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Already stopped, so it refreshes once and returns
+	stopped, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runWarm(stopped, opts, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := loadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := titles(cache.Posts), []string{"B", "A"}; !slices.Equal(got, want) {
+		t.Errorf("cached %q, want %q", got, want)
+	}
+
+	// A failed refresh leaves the cache as it was
+	f.files = nil
+	warmCache(opts)
+	if after, err := loadCache(path); err != nil || !after.FetchedAt.Equal(cache.FetchedAt) {
+		t.Errorf("failed refresh changed the cache: %+v, %v", after, err)
+	}
+
+	// Refreshes keep coming until it's stopped
+	f.files = map[string]string{"a.mdx": fixturePost("A", 1), "b.mdx": fixturePost("B", 2)}
+	os.Remove(path)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := runWarm(ctx, opts, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %s to stop", elapsed)
+	}
+	if repeat, err := loadCache(path); err != nil || !repeat.FetchedAt.After(cache.FetchedAt) {
+		t.Errorf("no refresh before stopping: %+v, %v", repeat, err)
+	}
+
+	if err := runWarm(stopped, opts, 0); err == nil {
+		t.Error("a zero --interval gave no error")
+	}
+}